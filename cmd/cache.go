@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/andrijan/bc-objects-counter/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the scan cache",
+	Long: `Scan results are cached per file in the user cache directory, so repeated
+scans only reparse files whose size, modification time or content changed.
+The cache is discarded automatically when the tool version changes.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached scan results",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cache.Dir()
+		if err != nil {
+			return fmt.Errorf("failed to locate cache directory: %w", err)
+		}
+		if err := cache.Clear(dir); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Printf("✓ Cleared %s\n", dir)
+		return nil
+	},
+}
+
+var cacheDirCmd = &cobra.Command{
+	Use:   "dir",
+	Short: "Print the cache directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cache.Dir()
+		if err != nil {
+			return fmt.Errorf("failed to locate cache directory: %w", err)
+		}
+		fmt.Println(dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheDirCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/andrijan/bc-objects-counter/internal/check"
+	"github.com/andrijan/bc-objects-counter/internal/export"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkOutputFormat string
+	checkOutputFile   string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check <path>",
+	Short: "Check AL objects for IDs outside the app's idRanges and duplicates",
+	Long: `Check scans a directory and reports problems that would otherwise only
+surface when the app is compiled or published:
+
+  id-range       an object, tableextension field or enumextension value is
+                 numbered outside the idRanges (or legacy idRange) of the
+                 app.json owning its file
+  no-id-ranges   an app.json declares no ID ranges, so its objects could not
+                 be checked
+  duplicate-id   an object ID is declared more than once for the same object
+                 type within an app, or reserved more than once in the app's
+                 .objectids.json ledger
+  duplicate-name an object name is declared more than once for the same
+                 object type and namespace within an app
+  reserved-id    an object or tableextension field uses an ID reserved in the
+                 ledger for a different name
+
+Each finding is reported with its file position. The command exits with an
+error when there are findings, after the report has been written.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&checkOutputFormat, "output", "o", "console", "Output format: console, json, xlsx, pdf, all")
+	checkCmd.Flags().StringVarP(&checkOutputFile, "file", "f", "", "Output filename (without extension, auto-generated if not specified)")
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	result, err := scanPathForCommand(cmd.Context(), args[0], false)
+	if err != nil {
+		return err
+	}
+
+	report, err := check.Run(result)
+	if err != nil {
+		return err
+	}
+
+	if checkOutputFile == "" {
+		checkOutputFile = defaultOutputName("bc-check")
+	}
+
+	err = writeReport(checkOutputFormat, checkOutputFile, reportWriters{
+		Console: func() string { return export.CheckToConsole(report) },
+		JSON:    func(path string) error { return export.CheckToJSON(report, path) },
+		Excel:   func(path string) error { return export.CheckToExcel(report, path) },
+		PDF:     func(path string) error { return export.CheckToPDF(report, path) },
+	})
+	if err != nil {
+		return err
+	}
+
+	if report.TotalFindings > 0 {
+		// Findings are not a usage error
+		cmd.SilenceUsage = true
+		return fmt.Errorf("check found %d problem(s)", report.TotalFindings)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andrijan/bc-objects-counter/internal/conflict"
+	"github.com/andrijan/bc-objects-counter/internal/export"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conflictsOutputFormat string
+	conflictsOutputFile   string
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts <repo> <ours> <theirs>",
+	Short: "Find object IDs and names added on two git branches that collide",
+	Long: `Conflicts reads two revisions of a local git repository and their merge base
+straight from its .git directory, without checking anything out, scans the
+AL sources of each and reports the objects introduced on both sides since the
+merge base that collide once the branches are merged:
+
+  ID     the same object type and ID, within the same app
+  name   the same object type, namespace and name, within the same app
+
+Revisions can be branches, tags, remote-tracking branches or commits, with
+optional ~N and ^N suffixes. The command exits with an error when there are
+collisions, after the report has been written.`,
+	Example: `  bc-objects-counter conflicts . HEAD origin/main
+  bc-objects-counter conflicts /path/to/repo feature/sales main -o xlsx`,
+	Args: cobra.ExactArgs(3),
+	RunE: runConflicts,
+}
+
+func init() {
+	conflictsCmd.Flags().StringVarP(&conflictsOutputFormat, "output", "o", "console", "Output format: console, json, xlsx, pdf, all")
+	conflictsCmd.Flags().StringVarP(&conflictsOutputFile, "file", "f", "", "Output filename (without extension, auto-generated if not specified)")
+	rootCmd.AddCommand(conflictsCmd)
+}
+
+func runConflicts(cmd *cobra.Command, args []string) error {
+	opts := scanner.Options{Jobs: jobs, Defines: defines}
+	report, err := conflict.Run(cmd.Context(), args[0], args[1], args[2], opts)
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("scan cancelled")
+	}
+	if err != nil {
+		return err
+	}
+
+	if conflictsOutputFile == "" {
+		conflictsOutputFile = defaultOutputName("bc-conflicts")
+	}
+
+	err = writeReport(conflictsOutputFormat, conflictsOutputFile, reportWriters{
+		Console: func() string { return export.ConflictsToConsole(report) },
+		JSON:    func(path string) error { return export.ConflictsToJSON(report, path) },
+		Excel:   func(path string) error { return export.ConflictsToExcel(report, path) },
+		PDF:     func(path string) error { return export.ConflictsToPDF(report, path) },
+	})
+	if err != nil {
+		return err
+	}
+
+	if report.TotalCollisions > 0 {
+		// Collisions are not a usage error
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d collision(s) between %s and %s", report.TotalCollisions, args[1], args[2])
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/andrijan/bc-objects-counter/internal/export"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertOutputFormat string
+	convertOutputFile   string
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert <summary.json>",
+	Short: "Convert a saved JSON summary to another output format",
+	Long: `Convert reads a summary saved with -o json and writes it in another output
+format, without scanning the sources again. Summaries written by earlier
+versions are read as well; converting to JSON upgrades them to the current
+schema version.`,
+	Example: `  bc-objects-counter convert release-1.0.json -o xlsx
+  bc-objects-counter convert release-1.0.json -o all -f release-1.0-report`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvert,
+}
+
+func init() {
+	convertCmd.Flags().StringVarP(&convertOutputFormat, "output", "o", "console", "Output format: console, json, xlsx, pdf, all")
+	convertCmd.Flags().StringVarP(&convertOutputFile, "file", "f", "", "Output filename (without extension, auto-generated if not specified)")
+	rootCmd.AddCommand(convertCmd)
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	summary, err := export.FromJSON(args[0])
+	if err != nil {
+		return err
+	}
+
+	if convertOutputFile == "" {
+		convertOutputFile = defaultOutputName("bc-objects")
+	}
+
+	return writeReport(convertOutputFormat, convertOutputFile, reportWriters{
+		Console: func() string { return export.ToConsole(summary) },
+		JSON:    func(path string) error { return export.ToJSON(summary, path) },
+		Excel:   func(path string) error { return export.ToExcel(summary, path) },
+		PDF:     func(path string) error { return export.ToPDF(summary, path) },
+	})
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/diff"
+	"github.com/andrijan/bc-objects-counter/internal/export"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffOutputFormat string
+	diffOutputFile   string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two scans and list the objects that changed",
+	Long: `Diff compares two snapshots of a code base, e.g. two releases, and lists the
+objects that were added, removed, renamed (same type and ID, new name),
+renumbered (same type and name, new ID) or moved to another file, with the
+change in the number of objects per type.
+
+Each snapshot is either a JSON file written by bc-objects-counter -o json, or
+a directory (or .al/.app file) that is scanned.`,
+	Example: `  bc-objects-counter diff release-1.0.json release-2.0.json
+  bc-objects-counter diff release-1.0.json /path/to/al/project -o md`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffOutputFormat, "output", "o", "console", "Output format: console, json, xlsx, md, all")
+	diffCmd.Flags().StringVarP(&diffOutputFile, "file", "f", "", "Output filename (without extension, auto-generated if not specified)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	oldSnapshot, err := loadSnapshot(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	newSnapshot, err := loadSnapshot(cmd, args[1])
+	if err != nil {
+		return err
+	}
+
+	report := diff.Compare(oldSnapshot, newSnapshot)
+
+	if diffOutputFile == "" {
+		diffOutputFile = defaultOutputName("bc-diff")
+	}
+
+	return writeReport(diffOutputFormat, diffOutputFile, reportWriters{
+		Console:  func() string { return export.DiffToConsole(report) },
+		JSON:     func(path string) error { return export.DiffToJSON(report, path) },
+		Excel:    func(path string) error { return export.DiffToExcel(report, path) },
+		Markdown: func(path string) error { return export.DiffToMarkdownFile(report, path) },
+	})
+}
+
+// loadSnapshot reads a JSON summary, or scans a directory or file.
+func loadSnapshot(cmd *cobra.Command, path string) (diff.Snapshot, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		summary, err := export.FromJSON(path)
+		if err != nil {
+			return diff.Snapshot{}, err
+		}
+		root := summary.Root
+		if root == "" {
+			// Summaries written before the root was recorded
+			root = diff.CommonRoot(summary.Objects)
+		}
+		return diff.Snapshot{Name: path, Root: root, Objects: summary.Objects}, nil
+	}
+
+	result, err := scanPathForCommand(cmd.Context(), path, false)
+	if err != nil {
+		return diff.Snapshot{}, err
+	}
+	absPath, _ := filepath.Abs(path)
+	return diff.Snapshot{Name: path, Root: scanRoot(absPath), Objects: result.Objects}, nil
+}
+
+// scanRoot returns the directory a scan of path covers: path itself, or the
+// directory of a single scanned file.
+func scanRoot(path string) string {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return filepath.Dir(path)
+	}
+	return path
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/andrijan/bc-objects-counter/internal/export"
+	"github.com/andrijan/bc-objects-counter/internal/ids"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	idsOutputFormat string
+	idsApp          string
+	idsCount        int
+	idsExtension    string
+	idsName         string
+	idsBy           string
+)
+
+var idsCmd = &cobra.Command{
+	Use:   "ids",
+	Short: "Find, reserve and release object and field IDs",
+	Long: `Ids finds free object and field IDs within the idRanges of an app.json and
+manages the app's reservation ledger (.objectids.json next to app.json).
+
+Commit the ledger with the app's sources: IDs reserved there are skipped by
+"ids next" and checked by "check", so developers on other branches do not
+pick them too.`,
+}
+
+var idsNextCmd = &cobra.Command{
+	Use:   "next <type> <path>",
+	Short: "Print the next free IDs for an object type",
+	Long: `Next scans a directory and prints the lowest IDs within the idRanges of an
+app.json that no object of the given type uses yet and that are not reserved
+in the app's ledger.
+
+Use the type "field" with --extension to get free field IDs for a
+tableextension instead. Field IDs already used by any tableextension of the
+app extending the same table (and by that table, if the app declares it) are
+skipped.
+
+When the directory contains several apps, choose one with --app. With
+-o json the result is printed to stdout as JSON for editor integrations.`,
+	Example: `  bc-objects-counter ids next codeunit .
+  bc-objects-counter ids next table . --app ./Sales -n 3
+  bc-objects-counter ids next field . --extension "Customer Ext" -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIDsNext,
+}
+
+var idsReserveCmd = &cobra.Command{
+	Use:   "reserve <type> <path> [id...]",
+	Short: "Reserve object or field IDs in the app's ledger",
+	Long: `Reserve records IDs in the app's .objectids.json ledger. Without explicit
+IDs, the next free IDs are reserved (see "ids next"); explicit IDs must lie
+within the app's idRanges and must not be used or reserved yet.
+
+The ledger is locked while it is updated, so concurrent reservations on the
+same machine do not overwrite each other.`,
+	Example: `  bc-objects-counter ids reserve codeunit . --name "Post Sales"
+  bc-objects-counter ids reserve table . 50110 50111
+  bc-objects-counter ids reserve field . --extension "Customer Ext" -o json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runIDsReserve,
+}
+
+var idsReleaseCmd = &cobra.Command{
+	Use:   "release <type> <path> <id>...",
+	Short: "Release reserved IDs from the app's ledger",
+	Args:  cobra.MinimumNArgs(3),
+	RunE:  runIDsRelease,
+}
+
+var idsListCmd = &cobra.Command{
+	Use:   "list <path>",
+	Short: "List the IDs reserved in the app's ledger",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runIDsList,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{idsNextCmd, idsReserveCmd, idsListCmd} {
+		c.Flags().StringVarP(&idsOutputFormat, "output", "o", "console", "Output format: console, json")
+	}
+	for _, c := range []*cobra.Command{idsNextCmd, idsReserveCmd, idsReleaseCmd, idsListCmd} {
+		c.Flags().StringVar(&idsApp, "app", "", "Folder of the app.json to use when the directory contains several apps")
+	}
+	for _, c := range []*cobra.Command{idsNextCmd, idsReserveCmd, idsReleaseCmd} {
+		c.Flags().StringVar(&idsExtension, "extension", "", "Name or ID of the tableextension for field IDs")
+	}
+	idsNextCmd.Flags().IntVarP(&idsCount, "count", "n", 1, "Number of free IDs to print")
+	idsReserveCmd.Flags().IntVarP(&idsCount, "count", "n", 1, "Number of free IDs to reserve when no IDs are given")
+	idsReserveCmd.Flags().StringVar(&idsName, "name", "", "Intended name of the object or field")
+	idsReserveCmd.Flags().StringVar(&idsBy, "by", "", "Who the IDs are reserved by (default: current user)")
+
+	idsCmd.AddCommand(idsNextCmd, idsReserveCmd, idsReleaseCmd, idsListCmd)
+	rootCmd.AddCommand(idsCmd)
+}
+
+// scanApp scans path and selects the app given by --app.
+func scanApp(cmd *cobra.Command, path string) (*scanner.Result, *scanner.AppManifest, error) {
+	result, err := scanPathForCommand(cmd.Context(), path, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	app, err := ids.SelectApp(result.Apps, idsApp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, app, nil
+}
+
+// idsQuery builds the query for an object type argument.
+func idsQuery(typeArg string) (ids.Query, error) {
+	q := ids.Query{Type: strings.ToLower(typeArg), Count: idsCount, Extension: idsExtension}
+	if q.Type == ids.TypeField && q.Extension == "" {
+		return q, fmt.Errorf("field IDs need --extension")
+	}
+	return q, nil
+}
+
+// parseIDs parses ID arguments.
+func parseIDs(args []string) ([]int, error) {
+	result := make([]int, len(args))
+	for i, arg := range args {
+		id, err := ids.ParseID(arg)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = id
+	}
+	return result, nil
+}
+
+// printIDs prints a result of free or reserved IDs.
+func printIDs(title string, result *ids.Result) error {
+	switch strings.ToLower(idsOutputFormat) {
+	case "console":
+		fmt.Print(export.IDsToConsole(title, result))
+	case "json":
+		data, err := export.IDsToJSONString(result)
+		if err != nil {
+			return err
+		}
+		fmt.Println(data)
+	default:
+		return fmt.Errorf("unknown output format: %s", idsOutputFormat)
+	}
+	return nil
+}
+
+// currentUser returns the name recorded with reservations by default.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+func runIDsNext(cmd *cobra.Command, args []string) error {
+	q, err := idsQuery(args[0])
+	if err != nil {
+		return err
+	}
+
+	result, app, err := scanApp(cmd, args[1])
+	if err != nil {
+		return err
+	}
+
+	// Errors past this point are about the app or its IDs, not usage
+	cmd.SilenceUsage = true
+
+	ledger, err := ids.LoadLedger(ids.LedgerPath(app))
+	if err != nil {
+		return err
+	}
+	next, err := ids.Next(result.Objects, app, ledger, q)
+	if err != nil {
+		return err
+	}
+	return printIDs("Next Free IDs", next)
+}
+
+func runIDsReserve(cmd *cobra.Command, args []string) error {
+	q, err := idsQuery(args[0])
+	if err != nil {
+		return err
+	}
+	wanted, err := parseIDs(args[2:])
+	if err != nil {
+		return err
+	}
+
+	result, app, err := scanApp(cmd, args[1])
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	by := idsBy
+	if by == "" {
+		by = currentUser()
+	}
+	date := time.Now().Format("2006-01-02")
+
+	// Pick and record the IDs while holding the lock, so that concurrent
+	// reservations see each other
+	var reserved *ids.Result
+	err = ids.UpdateLedger(ids.LedgerPath(app), func(ledger *ids.Ledger) error {
+		var err error
+		if len(wanted) > 0 {
+			reserved, err = ids.Take(result.Objects, app, q, wanted)
+		} else {
+			reserved, err = ids.Next(result.Objects, app, ledger, q)
+		}
+		if err != nil {
+			return err
+		}
+		for _, id := range reserved.IDs {
+			r := ids.Reservation{Type: q.Type, ID: id, Table: reserved.Table, Name: idsName, By: by, Date: date}
+			if err := ledger.Reserve(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return printIDs("Reserved IDs", reserved)
+}
+
+func runIDsRelease(cmd *cobra.Command, args []string) error {
+	q, err := idsQuery(args[0])
+	if err != nil {
+		return err
+	}
+	released, err := parseIDs(args[2:])
+	if err != nil {
+		return err
+	}
+
+	result, app, err := scanApp(cmd, args[1])
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	var table string
+	if q.Type == ids.TypeField {
+		if table, err = ids.ExtendedTable(result.Objects, app, q.Extension); err != nil {
+			return err
+		}
+	}
+
+	path := ids.LedgerPath(app)
+	var removed []ids.Reservation
+	err = ids.UpdateLedger(path, func(ledger *ids.Ledger) error {
+		for _, id := range released {
+			r, err := ledger.Release(q.Type, table, id)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, r...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range removed {
+		fmt.Printf("✓ Released %s from %s\n", r, path)
+	}
+	return nil
+}
+
+func runIDsList(cmd *cobra.Command, args []string) error {
+	_, app, err := scanApp(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	ledger, err := ids.LoadLedger(ids.LedgerPath(app))
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(idsOutputFormat) {
+	case "console":
+		fmt.Print(export.ReservationsToConsole(app.Info(), ledger))
+	case "json":
+		data, err := ledger.Marshal()
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	default:
+		return fmt.Errorf("unknown output format: %s", idsOutputFormat)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/andrijan/bc-objects-counter/internal/export"
+	"github.com/andrijan/bc-objects-counter/internal/migrate"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateOutputFormat string
+	migrateOutputFile   string
+	migrateAll          bool
+)
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "migrate-status <cal-export> <al-path>",
+	Short: "Compare C/AL objects against their converted AL objects",
+	Long: `Migrate-status compares the objects of a C/AL text export (a .txt file or a
+directory of them) against an AL source tree and reports, per C/AL object,
+whether it has been converted (an AL object with the same type and ID or
+name), partially converted (only table/page/report extensions target it) or
+is still missing.
+
+By default only objects marked Modified=Yes are compared; use --all to
+include unmodified standard objects.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMigrateStatus,
+}
+
+func init() {
+	migrateStatusCmd.Flags().StringVarP(&migrateOutputFormat, "output", "o", "console", "Output format: console, json, xlsx, pdf, all")
+	migrateStatusCmd.Flags().StringVarP(&migrateOutputFile, "file", "f", "", "Output filename (without extension, auto-generated if not specified)")
+	migrateStatusCmd.Flags().BoolVar(&migrateAll, "all", false, "Include C/AL objects that are not marked as modified")
+	rootCmd.AddCommand(migrateStatusCmd)
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) error {
+	calResult, err := scanPathForCommand(cmd.Context(), args[0], true)
+	if err != nil {
+		return err
+	}
+	alResult, err := scanPathForCommand(cmd.Context(), args[1], false)
+	if err != nil {
+		return err
+	}
+
+	// Split by origin: the C/AL side only counts text export objects, the AL
+	// side everything else
+	var calObjects, alObjects []scanner.BCObject
+	for _, obj := range calResult.Objects {
+		if obj.CAL != nil {
+			calObjects = append(calObjects, obj)
+		}
+	}
+	for _, obj := range alResult.Objects {
+		if obj.CAL == nil {
+			alObjects = append(alObjects, obj)
+		}
+	}
+	if len(calObjects) == 0 {
+		return fmt.Errorf("no C/AL objects found in %s", args[0])
+	}
+	if !migrateAll {
+		calObjects = migrate.FilterModified(calObjects)
+	}
+
+	report := migrate.Compare(calObjects, alObjects)
+
+	if migrateOutputFile == "" {
+		migrateOutputFile = defaultOutputName("bc-migration")
+	}
+
+	return writeReport(migrateOutputFormat, migrateOutputFile, reportWriters{
+		Console: func() string { return export.MigrationToConsole(report) },
+		JSON:    func(path string) error { return export.MigrationToJSON(report, path) },
+		Excel:   func(path string) error { return export.MigrationToExcel(report, path) },
+		PDF:     func(path string) error { return export.MigrationToPDF(report, path) },
+	})
+}
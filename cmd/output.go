@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reportWriters bundles the functions that render one kind of report in each
+// output format. Formats a report does not support are left nil.
+type reportWriters struct {
+	Console func() string
+	JSON    func(path string) error
+	Excel   func(path string) error
+	PDF     func(path string) error
+	// Markdown is only offered by reports meant to be pasted into
+	// documents, such as release notes.
+	Markdown func(path string) error
+}
+
+// defaultOutputName returns a timestamped output filename (without extension).
+func defaultOutputName(prefix string) string {
+	timestamp := time.Now().Format("20060102-150405")
+	return fmt.Sprintf("%s-%s", prefix, timestamp)
+}
+
+// writeReport renders a report in the requested format: console, json,
+// xlsx (or excel), pdf, md (or markdown), or all of them. baseName is the
+// output filename without extension.
+func writeReport(format, baseName string, w reportWriters) error {
+	writeFile := func(label, ext string, write func(string) error) error {
+		if write == nil {
+			return fmt.Errorf("%s output is not supported for this report", label)
+		}
+		path := baseName + ext
+		if err := write(path); err != nil {
+			return fmt.Errorf("failed to export %s: %w", label, err)
+		}
+		fmt.Printf("✓ Exported to %s\n", path)
+		return nil
+	}
+
+	switch strings.ToLower(format) {
+	case "console":
+		fmt.Print(w.Console())
+		return nil
+
+	case "json":
+		return writeFile("JSON", ".json", w.JSON)
+
+	case "xlsx", "excel":
+		return writeFile("Excel", ".xlsx", w.Excel)
+
+	case "pdf":
+		return writeFile("PDF", ".pdf", w.PDF)
+
+	case "md", "markdown":
+		return writeFile("Markdown", ".md", w.Markdown)
+
+	case "all":
+		// Print console output, then export every supported format
+		fmt.Print(w.Console())
+		if w.JSON != nil {
+			if err := writeFile("JSON", ".json", w.JSON); err != nil {
+				return err
+			}
+		}
+		if w.Excel != nil {
+			if err := writeFile("Excel", ".xlsx", w.Excel); err != nil {
+				return err
+			}
+		}
+		if w.PDF != nil {
+			if err := writeFile("PDF", ".pdf", w.PDF); err != nil {
+				return err
+			}
+		}
+		if w.Markdown != nil {
+			if err := writeFile("Markdown", ".md", w.Markdown); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
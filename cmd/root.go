@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"strings"
-	"time"
+	"syscall"
 
+	"github.com/andrijan/bc-objects-counter/internal/cache"
 	"github.com/andrijan/bc-objects-counter/internal/counter"
 	"github.com/andrijan/bc-objects-counter/internal/export"
 	"github.com/andrijan/bc-objects-counter/internal/scanner"
@@ -21,6 +24,16 @@ var (
 	outputFile   string
 	recursive    bool
 	verbose      bool
+	strict       bool
+	jobs         int
+	noCache      bool
+	includeApps  bool
+	includeCAL   bool
+	defines      []string
+	showInactive bool
+	groupBy      string
+	topComplex   int
+	maxComplex   int
 )
 
 var rootCmd = &cobra.Command{
@@ -29,23 +42,47 @@ var rootCmd = &cobra.Command{
 	Long: `BC Objects Counter scans a directory for Business Central AL files
 and counts all object types (tables, pages, codeunits, etc.).
 
+Preprocessor directives (#if/#elif/#else/#endif) are evaluated, so objects in
+inactive branches are not counted. Symbols are taken from the preprocessorSymbols
+of the app.json owning each file and from --define.
+
+Compiled .app packages can be inventoried from their symbol metadata, either
+by passing a single .app file as path or with --include-apps.
+
+Legacy C/AL text exports (.txt) are counted when passed as path or with
+--include-cal.
+
 It can export the results to JSON, Excel, or PDF format.
 
 Arguments:
-  path    Directory path to scan for .al files (required)`,
+  path    Directory (or single .al/.app file) to scan (required)`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCounter,
 }
 
+// Execute runs the root command. Ctrl-C (or SIGTERM) cancels the command's
+// context so long-running scans stop cleanly.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
 	rootCmd.Flags().StringVarP(&outputFormat, "output", "o", "console", "Output format: console, json, xlsx, pdf, all")
 	rootCmd.Flags().StringVarP(&outputFile, "file", "f", "", "Output filename (without extension, auto-generated if not specified)")
 	rootCmd.Flags().BoolVarP(&recursive, "recursive", "r", true, "Scan subdirectories")
-	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed output")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed output")
+	rootCmd.PersistentFlags().IntVarP(&jobs, "jobs", "j", 0, "Number of files to scan concurrently (0 = number of CPUs)")
+	rootCmd.Flags().BoolVar(&includeApps, "include-apps", false, "Also count objects in compiled .app packages found in the directory")
+	rootCmd.Flags().BoolVar(&includeCAL, "include-cal", false, "Also count objects in C/AL text exports (.txt) found in the directory")
+	rootCmd.PersistentFlags().StringArrayVarP(&defines, "define", "D", nil, "Preprocessor symbol to treat as defined (repeatable)")
+	rootCmd.Flags().BoolVar(&showInactive, "show-inactive", false, "List objects declared in inactive #if branches separately")
+	rootCmd.Flags().StringVar(&groupBy, "group-by", "", "Also count objects by the value of an object property, e.g. PageType or Subtype")
+	rootCmd.Flags().IntVar(&topComplex, "top-complex", counter.DefaultTopComplex, "Number of most complex procedures and triggers to list")
+	rootCmd.Flags().IntVar(&maxComplex, "max-complexity", 0, "Exit with an error if any procedure or trigger exceeds this cyclomatic complexity (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Reparse every file instead of reusing cached results")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "Exit with an error if the scan reports any error diagnostics")
 	rootCmd.Version = Version
 	rootCmd.SetVersionTemplate("bc-objects-counter version {{.Version}}\n")
 }
@@ -70,79 +107,144 @@ func runCounter(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Recursive: %v\n", recursive)
 	}
 
+	opts := scanner.Options{
+		Recursive:   recursive,
+		Jobs:        jobs,
+		IncludeApps: includeApps,
+		IncludeCAL:  includeCAL,
+		Defines:     defines,
+	}
+
+	// Reuse results for unchanged files from earlier runs
+	scanCache := openScanCache(absPath)
+	if scanCache != nil {
+		opts.Cache = scanCache
+	}
+
 	// Scan for objects
-	objects, err := scanner.ScanDirectory(absPath, recursive)
+	result, err := scanner.ScanDirectoryContext(cmd.Context(), absPath, opts)
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("scan cancelled")
+	}
 	if err != nil {
 		return fmt.Errorf("scan failed: %w", err)
 	}
 
+	saveScanCache(scanCache)
+
 	if verbose {
-		fmt.Printf("Found %d objects\n", len(objects))
+		fmt.Printf("Found %d objects\n", len(result.Objects))
+		fmt.Fprint(os.Stderr, export.FormatDiagnostics(result.Diagnostics))
+	}
+
+	if !showInactive {
+		result.InactiveObjects = nil
 	}
 
 	// Create summary
-	summary := counter.CountObjects(objects)
+	summary := counter.Summarize(result)
+	summary.Root = scanRoot(absPath)
+	if groupBy != "" {
+		summary.GroupBy(groupBy)
+	}
+	summary.TopComplex(topComplex)
 
 	// Generate output filename if not specified
 	if outputFile == "" {
-		timestamp := time.Now().Format("20060102-150405")
-		outputFile = fmt.Sprintf("bc-objects-%s", timestamp)
+		outputFile = defaultOutputName("bc-objects")
 	}
 
-	// Handle output formats
-	format := strings.ToLower(outputFormat)
-
-	switch format {
-	case "console":
-		fmt.Print(export.ToConsole(summary))
+	err = writeReport(outputFormat, outputFile, reportWriters{
+		Console: func() string { return export.ToConsole(summary) },
+		JSON:    func(path string) error { return export.ToJSON(summary, path) },
+		Excel:   func(path string) error { return export.ToExcel(summary, path) },
+		PDF:     func(path string) error { return export.ToPDF(summary, path) },
+	})
+	if err != nil {
+		return err
+	}
 
-	case "json":
-		jsonFile := outputFile + ".json"
-		if err := export.ToJSON(summary, jsonFile); err != nil {
-			return fmt.Errorf("failed to export JSON: %w", err)
+	// In strict mode, incomplete scans fail the run after the reports are written
+	if strict {
+		if errorCount := summary.CountDiagnostics(scanner.SeverityError); errorCount > 0 {
+			return fmt.Errorf("strict mode: scan reported %d error diagnostic(s)", errorCount)
 		}
-		fmt.Printf("✓ Exported to %s\n", jsonFile)
+	}
 
-	case "xlsx", "excel":
-		xlsxFile := outputFile + ".xlsx"
-		if err := export.ToExcel(summary, xlsxFile); err != nil {
-			return fmt.Errorf("failed to export Excel: %w", err)
+	// Likewise, routines above the complexity threshold fail the run
+	if maxComplex > 0 {
+		if over := summary.ExceedingComplexity(maxComplex); len(over) > 0 {
+			fmt.Fprint(os.Stderr, export.FormatComplexityViolations(over, maxComplex))
+			return fmt.Errorf("%d procedure(s) or trigger(s) exceed the maximum complexity of %d", len(over), maxComplex)
 		}
-		fmt.Printf("✓ Exported to %s\n", xlsxFile)
+	}
 
-	case "pdf":
-		pdfFile := outputFile + ".pdf"
-		if err := export.ToPDF(summary, pdfFile); err != nil {
-			return fmt.Errorf("failed to export PDF: %w", err)
-		}
-		fmt.Printf("✓ Exported to %s\n", pdfFile)
+	return nil
+}
 
-	case "all":
-		// Print console output
-		fmt.Print(export.ToConsole(summary))
+// scanPathForCommand scans a file or directory recursively for subcommands
+// that compare scan results. It honours --jobs, --define and --no-cache like
+// the main command and reports diagnostics on stderr with --verbose.
+// includeCAL also reads the C/AL text exports in a directory.
+func scanPathForCommand(ctx context.Context, path string, includeCAL bool) (*scanner.Result, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("path does not exist: %s", absPath)
+	}
 
-		// Export all formats
-		jsonFile := outputFile + ".json"
-		if err := export.ToJSON(summary, jsonFile); err != nil {
-			return fmt.Errorf("failed to export JSON: %w", err)
-		}
-		fmt.Printf("✓ Exported to %s\n", jsonFile)
+	opts := scanner.Options{
+		Recursive:  true,
+		Jobs:       jobs,
+		IncludeCAL: includeCAL,
+		Defines:    defines,
+	}
+	scanCache := openScanCache(absPath)
+	if scanCache != nil {
+		opts.Cache = scanCache
+	}
 
-		xlsxFile := outputFile + ".xlsx"
-		if err := export.ToExcel(summary, xlsxFile); err != nil {
-			return fmt.Errorf("failed to export Excel: %w", err)
-		}
-		fmt.Printf("✓ Exported to %s\n", xlsxFile)
+	result, err := scanner.ScanDirectoryContext(ctx, absPath, opts)
+	if errors.Is(err, context.Canceled) {
+		return nil, fmt.Errorf("scan cancelled")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan of %s failed: %w", absPath, err)
+	}
 
-		pdfFile := outputFile + ".pdf"
-		if err := export.ToPDF(summary, pdfFile); err != nil {
-			return fmt.Errorf("failed to export PDF: %w", err)
-		}
-		fmt.Printf("✓ Exported to %s\n", pdfFile)
+	saveScanCache(scanCache)
 
-	default:
-		return fmt.Errorf("unknown output format: %s", outputFormat)
+	if verbose {
+		fmt.Fprint(os.Stderr, export.FormatDiagnostics(result.Diagnostics))
 	}
+	return result, nil
+}
 
-	return nil
+// openScanCache opens the cache of earlier scans of absPath, or returns nil
+// when --no-cache is given or the cache directory is unavailable.
+func openScanCache(absPath string) *cache.FileCache {
+	if noCache {
+		return nil
+	}
+	dir, err := cache.Dir()
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Cache disabled: %v\n", err)
+		}
+		return nil
+	}
+	return cache.Open(cache.PathFor(dir, absPath), Version)
+}
+
+// saveScanCache writes scanCache back to disk, warning on failure. A nil
+// cache is ignored.
+func saveScanCache(scanCache *cache.FileCache) {
+	if scanCache == nil {
+		return
+	}
+	if err := scanCache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save scan cache: %v\n", err)
+	}
 }
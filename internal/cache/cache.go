@@ -0,0 +1,210 @@
+// Package cache provides an on-disk cache of per-file scan results so that
+// repeated scans of an unchanged tree only reparse files that changed.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// formatVersion identifies the layout of the cache file itself.
+const formatVersion = 1
+
+// entry is the cached state of a single file.
+type entry struct {
+	Size    int64               `json:"size"`
+	ModTime int64               `json:"modTime"`
+	Hash    string              `json:"hash"`
+	Result  *scanner.FileResult `json:"result"`
+}
+
+// cacheFile is the on-disk representation of a FileCache.
+type cacheFile struct {
+	FormatVersion int               `json:"formatVersion"`
+	ResultVersion int               `json:"resultVersion"`
+	ToolVersion   string            `json:"toolVersion"`
+	Entries       map[string]*entry `json:"entries"`
+}
+
+// FileCache is a scanner.Cache persisted as a JSON file. Entries are
+// validated by file size and modification time, falling back to a SHA-256
+// content hash, and the whole cache is discarded when the tool version or
+// the scanner's result version changes.
+type FileCache struct {
+	path        string
+	toolVersion string
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	seen    map[string]bool
+	dirty   bool
+}
+
+// Dir returns the directory holding all cache files, inside the user cache dir.
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "bc-objects-counter"), nil
+}
+
+// PathFor returns the cache file used for scans of root within dir.
+func PathFor(dir, root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// Clear removes every cache file in dir.
+func Clear(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+// Open loads the cache stored at path. A missing, unreadable or outdated
+// cache file is not an error; the cache simply starts empty.
+func Open(path, toolVersion string) *FileCache {
+	c := &FileCache{
+		path:        path,
+		toolVersion: toolVersion,
+		entries:     make(map[string]*entry),
+		seen:        make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return c
+	}
+	if file.FormatVersion != formatVersion ||
+		file.ResultVersion != scanner.ResultVersion ||
+		file.ToolVersion != toolVersion {
+		// Stale cache; it is rewritten in full on Save
+		c.dirty = true
+		return c
+	}
+
+	if file.Entries != nil {
+		c.entries = file.Entries
+	}
+	return c
+}
+
+// Lookup implements scanner.Cache.
+func (c *FileCache) Lookup(key string, info os.FileInfo, src []byte) (*scanner.FileResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || e.Result == nil {
+		return nil, false
+	}
+
+	if src == nil {
+		if e.Size != info.Size() || e.ModTime != info.ModTime().UnixNano() {
+			return nil, false
+		}
+		c.seen[key] = true
+		return e.Result, true
+	}
+
+	// Size or mtime changed (e.g. after a fresh checkout); the content may not have
+	if e.Hash != hashOf(src) {
+		return nil, false
+	}
+	e.Size = info.Size()
+	e.ModTime = info.ModTime().UnixNano()
+	c.seen[key] = true
+	c.dirty = true
+	return e.Result, true
+}
+
+// Store implements scanner.Cache.
+func (c *FileCache) Store(key string, info os.FileInfo, src []byte, result *scanner.FileResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &entry{
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Hash:    hashOf(src),
+		Result:  result,
+	}
+	c.seen[key] = true
+	c.dirty = true
+}
+
+// Save writes the cache back to disk if it changed. Entries for files that
+// were not looked up or stored since Open (e.g. deleted files) are dropped.
+func (c *FileCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if !c.seen[key] {
+			delete(c.entries, key)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheFile{
+		FormatVersion: formatVersion,
+		ResultVersion: scanner.ResultVersion,
+		ToolVersion:   c.toolVersion,
+		Entries:       c.entries,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so an interrupted save never leaves a
+	// truncated cache behind
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Len returns the number of cached files.
+func (c *FileCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func hashOf(src []byte) string {
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+func writeFile(t *testing.T, path, content string) os.FileInfo {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestFileCacheLookup(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache", "scan.json")
+	alFile := filepath.Join(tmpDir, "table.al")
+	content := `table 50100 "Cached Table" { }`
+	info := writeFile(t, alFile, content)
+
+	result := &scanner.FileResult{Findings: scanner.Findings{
+		Objects: []scanner.BCObject{{Type: "table", ID: "50100", Name: "Cached Table", FilePath: alFile}},
+	}}
+
+	c := Open(cachePath, "1.0.0")
+	c.Store(alFile, info, []byte(content), result)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unchanged size and mtime: hit without reading the file
+	c = Open(cachePath, "1.0.0")
+	cached, ok := c.Lookup(alFile, info, nil)
+	if !ok || len(cached.Objects) != 1 || cached.Objects[0].Name != "Cached Table" {
+		t.Fatalf("expected cache hit, got %v %+v", ok, cached)
+	}
+
+	// Touched but identical content: miss on metadata, hit on hash
+	later := info.ModTime().Add(time.Hour)
+	if err := os.Chtimes(alFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	touched, err := os.Stat(alFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Lookup(alFile, touched, nil); ok {
+		t.Error("expected metadata miss after mtime change")
+	}
+	if _, ok := c.Lookup(alFile, touched, []byte(content)); !ok {
+		t.Error("expected hash hit for unchanged content")
+	}
+
+	// Changed content: miss
+	changed := writeFile(t, alFile, `table 50101 "Other" { }`)
+	if _, ok := c.Lookup(alFile, changed, []byte(`table 50101 "Other" { }`)); ok {
+		t.Error("expected miss for changed content")
+	}
+}
+
+func TestFileCacheToolVersionInvalidates(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "scan.json")
+	alFile := filepath.Join(tmpDir, "table.al")
+	info := writeFile(t, alFile, "x")
+
+	c := Open(cachePath, "1.0.0")
+	c.Store(alFile, info, []byte("x"), &scanner.FileResult{})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := Open(cachePath, "1.0.0").Len(); n != 1 {
+		t.Errorf("expected 1 entry for same version, got %d", n)
+	}
+	if n := Open(cachePath, "1.1.0").Len(); n != 0 {
+		t.Errorf("expected empty cache after version change, got %d entries", n)
+	}
+}
+
+func TestFileCacheSavePrunesUnseen(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "scan.json")
+	kept := filepath.Join(tmpDir, "kept.al")
+	deleted := filepath.Join(tmpDir, "deleted.al")
+	keptInfo := writeFile(t, kept, "a")
+	deletedInfo := writeFile(t, deleted, "b")
+
+	c := Open(cachePath, "dev")
+	c.Store(kept, keptInfo, []byte("a"), &scanner.FileResult{})
+	c.Store(deleted, deletedInfo, []byte("b"), &scanner.FileResult{})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c = Open(cachePath, "dev")
+	c.Lookup(kept, keptInfo, nil)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := Open(cachePath, "dev").Len(); n != 1 {
+		t.Errorf("expected 1 entry after pruning, got %d", n)
+	}
+}
+
+func TestScanDirectoryWithCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	if err := os.Mkdir(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(srcDir, "a.al"), `table 50100 "A" { }`)
+	writeFile(t, filepath.Join(srcDir, "b.al"), `page 50100 "B" { }`)
+
+	cachePath := PathFor(filepath.Join(tmpDir, "cache"), srcDir)
+	scan := func() *scanner.Result {
+		c := Open(cachePath, "dev")
+		result, err := scanner.ScanDirectoryContext(context.Background(), srcDir, scanner.Options{Recursive: true, Cache: c})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	first := scan()
+	second := scan()
+
+	if len(first.Objects) != 2 || len(second.Objects) != 2 {
+		t.Fatalf("expected 2 objects on both runs, got %d and %d", len(first.Objects), len(second.Objects))
+	}
+	for i := range first.Objects {
+		if first.Objects[i].Name != second.Objects[i].Name || first.Objects[i].Line != second.Objects[i].Line {
+			t.Errorf("object %d differs between fresh and cached scan", i)
+		}
+	}
+	if n := Open(cachePath, "dev").Len(); n != 2 {
+		t.Errorf("expected 2 cached files, got %d", n)
+	}
+}
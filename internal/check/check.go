@@ -0,0 +1,387 @@
+// Package check verifies AL sources against rules that are otherwise only
+// enforced when the app is compiled or published, such as the app's object ID
+// ranges.
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/ids"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// Rules reported in findings.
+const (
+	// RuleIDRange flags objects, tableextension fields and enumextension
+	// values numbered outside the idRanges of their app.json.
+	RuleIDRange = "id-range"
+	// RuleNoIDRanges flags an app.json without idRanges whose objects could
+	// therefore not be checked.
+	RuleNoIDRanges = "no-id-ranges"
+	// RuleDuplicateID flags every declaration of an object type and ID
+	// declared more than once in an app.
+	RuleDuplicateID = "duplicate-id"
+	// RuleDuplicateName flags every declaration of an object type and name
+	// declared more than once in an app (and namespace).
+	RuleDuplicateName = "duplicate-name"
+	// RuleReservedID flags objects and tableextension fields using an ID
+	// reserved in the app's .objectids.json ledger for a different name.
+	RuleReservedID = "reserved-id"
+)
+
+// Finding is a single rule violation. Member fields are set when the finding
+// concerns a field or enum value rather than the object itself.
+type Finding struct {
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	App        string `json:"app,omitempty"`
+	ObjectType string `json:"objectType,omitempty"`
+	ObjectID   string `json:"objectId,omitempty"`
+	ObjectName string `json:"objectName,omitempty"`
+	MemberKind string `json:"memberKind,omitempty"`
+	MemberID   string `json:"memberId,omitempty"`
+	MemberName string `json:"memberName,omitempty"`
+}
+
+// String formats the finding like a compiler message, e.g.
+// "src/Setup.Table.al:1:1: id-range: table 70000 ...".
+func (f Finding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", location, f.Line, f.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, f.Rule, f.Message)
+}
+
+// RuleCount is the number of findings for a rule.
+type RuleCount struct {
+	Rule  string `json:"rule"`
+	Count int    `json:"count"`
+}
+
+// Report is the result of checking a scan result.
+type Report struct {
+	TotalFindings int         `json:"totalFindings"`
+	CountsByRule  []RuleCount `json:"countsByRule"`
+	Findings      []Finding   `json:"findings"`
+}
+
+// GetCountByRule returns the number of findings for a rule.
+func (r *Report) GetCountByRule(rule string) int {
+	for _, c := range r.CountsByRule {
+		if c.Rule == rule {
+			return c.Count
+		}
+	}
+	return 0
+}
+
+// Run checks a scan result, including the reservation ledgers of its apps,
+// and returns the findings sorted by file and position.
+func Run(result *scanner.Result) (*Report, error) {
+	findings := CheckIDRanges(result.Objects, result.Apps)
+	findings = append(findings, CheckDuplicates(result.Objects)...)
+
+	reservations, err := CheckReservations(result.Objects, result.Apps)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, reservations...)
+	return newReport(findings), nil
+}
+
+// newReport sorts findings and counts them per rule.
+func newReport(findings []Finding) *Report {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Rule]++
+	}
+	report := &Report{TotalFindings: len(findings), Findings: findings}
+	for rule, count := range counts {
+		report.CountsByRule = append(report.CountsByRule, RuleCount{Rule: rule, Count: count})
+	}
+	sort.Slice(report.CountsByRule, func(i, j int) bool {
+		return report.CountsByRule[i].Rule < report.CountsByRule[j].Rule
+	})
+	return report
+}
+
+// idMembers maps the extension object types whose members must be numbered
+// within the app's ID ranges to the member kind concerned.
+var idMembers = map[string]string{
+	"tableextension": scanner.MemberField,
+	"enumextension":  scanner.MemberValue,
+}
+
+// CheckIDRanges reports objects, tableextension fields and enumextension
+// values whose IDs lie outside the ID ranges of the app they belong to.
+// Objects read from .app packages or C/AL exports and objects outside any of
+// the given apps are not checked.
+func CheckIDRanges(objects []scanner.BCObject, apps []scanner.AppManifest) []Finding {
+	var findings []Finding
+	unchecked := make(map[int]int) // app index -> objects without ranges
+
+	for _, obj := range objects {
+		if obj.App == nil || obj.ID == "" || !obj.FromSource() {
+			continue
+		}
+		index := appIndex(apps, *obj.App)
+		if index < 0 {
+			continue
+		}
+		ranges := apps[index].Ranges()
+		if len(ranges) == 0 {
+			unchecked[index]++
+			continue
+		}
+
+		if !inRanges(ranges, obj.ID) {
+			findings = append(findings, Finding{
+				Rule: RuleIDRange,
+				Message: fmt.Sprintf("%s %s %s is outside the app's ID ranges (%s)",
+					obj.Type, obj.ID, quoteName(obj.Name), scanner.FormatRanges(ranges)),
+				File:       obj.FilePath,
+				Line:       obj.Line,
+				Column:     obj.Column,
+				App:        obj.App.Label(),
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+			})
+		}
+
+		kind, ok := idMembers[obj.Type]
+		if !ok {
+			continue
+		}
+		for _, m := range obj.Members {
+			if m.Kind != kind || m.ID == "" || inRanges(ranges, m.ID) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule: RuleIDRange,
+				Message: fmt.Sprintf("%s %s %s in %s %s is outside the app's ID ranges (%s)",
+					m.Kind, m.ID, quoteName(m.Name), obj.Type, quoteName(obj.Name), scanner.FormatRanges(ranges)),
+				File:       obj.FilePath,
+				Line:       m.Line,
+				Column:     m.Column,
+				App:        obj.App.Label(),
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+				MemberKind: m.Kind,
+				MemberID:   m.ID,
+				MemberName: m.Name,
+			})
+		}
+	}
+
+	for index, count := range unchecked {
+		app := apps[index]
+		findings = append(findings, Finding{
+			Rule:    RuleNoIDRanges,
+			Message: fmt.Sprintf("app.json declares no idRanges; %d object(s) were not checked", count),
+			File:    app.Path(),
+			App:     app.Info().Label(),
+		})
+	}
+
+	return findings
+}
+
+// CheckDuplicates reports every declaration of an object ID or name that is
+// declared more than once within an app, as found by counter.FindDuplicates.
+func CheckDuplicates(objects []scanner.BCObject) []Finding {
+	var findings []Finding
+	for _, d := range counter.FindDuplicates(objects) {
+		rule, what := RuleDuplicateID, "ID "+d.Value
+		if d.Kind == counter.DuplicateName {
+			rule, what = RuleDuplicateName, "name "+quoteName(d.Value)
+		}
+
+		for i, obj := range d.Objects {
+			var others []string
+			for j, other := range d.Objects {
+				if j != i {
+					others = append(others, fmt.Sprintf("%s:%d", other.FilePath, other.Line))
+				}
+			}
+			finding := Finding{
+				Rule: rule,
+				Message: fmt.Sprintf("%s %s %s reuses the %s %s, also declared at %s",
+					obj.Type, obj.ID, quoteName(obj.Name), d.Type, what, strings.Join(others, ", ")),
+				File:       obj.FilePath,
+				Line:       obj.Line,
+				Column:     obj.Column,
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+			}
+			if obj.App != nil {
+				finding.App = obj.App.Label()
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// CheckReservations reads the reservation ledger of each app and reports IDs
+// reserved more than once, as duplicate-id findings on the ledger, and
+// objects or tableextension fields using an ID reserved for another name.
+func CheckReservations(objects []scanner.BCObject, apps []scanner.AppManifest) ([]Finding, error) {
+	ledgers := make([]*ids.Ledger, len(apps))
+	var findings []Finding
+	for i := range apps {
+		path := ids.LedgerPath(&apps[i])
+		ledger, err := ids.LoadLedger(path)
+		if err != nil {
+			return nil, err
+		}
+		ledgers[i] = ledger
+
+		for _, group := range ledger.Duplicates() {
+			reserved := make([]string, len(group))
+			for j, r := range group {
+				reserved[j] = reservationOwner(r)
+			}
+			first := group[0]
+			findings = append(findings, Finding{
+				Rule: RuleDuplicateID,
+				Message: fmt.Sprintf("%s is reserved %d times: %s",
+					ids.Reservation{Type: first.Type, ID: first.ID, Table: first.Table}, len(group), strings.Join(reserved, ", ")),
+				File: path,
+				App:  apps[i].Info().Label(),
+			})
+		}
+	}
+
+	for _, obj := range objects {
+		if obj.App == nil || !obj.FromSource() {
+			continue
+		}
+		index := appIndex(apps, *obj.App)
+		if index < 0 || len(ledgers[index].Reservations) == 0 {
+			continue
+		}
+		ledger := ledgers[index]
+
+		if r := conflictingReservation(ledger.Find(obj.Type, ""), obj.ID, obj.Name); r != nil {
+			findings = append(findings, Finding{
+				Rule: RuleReservedID,
+				Message: fmt.Sprintf("%s %s %s uses an ID reserved for %s",
+					obj.Type, obj.ID, quoteName(obj.Name), reservationOwner(*r)),
+				File:       obj.FilePath,
+				Line:       obj.Line,
+				Column:     obj.Column,
+				App:        obj.App.Label(),
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+			})
+		}
+
+		if obj.Type != "tableextension" {
+			continue
+		}
+		fields := ledger.Find(ids.TypeField, scanner.UnqualifiedName(obj.Extends))
+		for _, m := range obj.Members {
+			if m.Kind != scanner.MemberField {
+				continue
+			}
+			r := conflictingReservation(fields, m.ID, m.Name)
+			if r == nil {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule: RuleReservedID,
+				Message: fmt.Sprintf("%s %s %s in %s %s uses an ID reserved for %s",
+					m.Kind, m.ID, quoteName(m.Name), obj.Type, quoteName(obj.Name), reservationOwner(*r)),
+				File:       obj.FilePath,
+				Line:       m.Line,
+				Column:     m.Column,
+				App:        obj.App.Label(),
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+				MemberKind: m.Kind,
+				MemberID:   m.ID,
+				MemberName: m.Name,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// conflictingReservation returns the reservation of id for a name other than
+// name, or nil. Reservations without a name never conflict.
+func conflictingReservation(reservations []ids.Reservation, id, name string) *ids.Reservation {
+	for i, r := range reservations {
+		if strconv.Itoa(r.ID) == id && r.Name != "" && !strings.EqualFold(r.Name, name) {
+			return &reservations[i]
+		}
+	}
+	return nil
+}
+
+// reservationOwner describes whom a reservation is for, e.g.
+// `"Post Sales" by alice`.
+func reservationOwner(r ids.Reservation) string {
+	owner := quoteName(r.Name)
+	if r.Name == "" {
+		owner = "(unnamed)"
+	}
+	if r.By != "" {
+		owner += " by " + r.By
+	}
+	return owner
+}
+
+// appIndex returns the index of the manifest describing app, or -1.
+func appIndex(apps []scanner.AppManifest, app scanner.AppInfo) int {
+	for i := range apps {
+		if *apps[i].Info() == app {
+			return i
+		}
+	}
+	return -1
+}
+
+// inRanges reports whether the numeric id lies in one of ranges. IDs that are
+// not numbers are never in range.
+func inRanges(ranges []scanner.IDRange, id string) bool {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return false
+	}
+	for _, r := range ranges {
+		if r.Contains(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteName quotes a name for messages.
+func quoteName(name string) string {
+	return `"` + name + `"`
+}
@@ -0,0 +1,194 @@
+package check
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/andrijan/bc-objects-counter/internal/testutil"
+)
+
+func TestRunIDRanges(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"main/app.json": `{"id": "1", "name": "Sales", "idRanges": [{"from": 50100, "to": 50149}, {"from": 70000, "to": 70009}]}`,
+		"main/src/Objects.al": `table 50100 "Sales Setup"
+{
+}
+
+codeunit 60000 "Out Of Range"
+{
+}
+
+tableextension 70000 "Customer Ext" extends Customer
+{
+    fields
+    {
+        field(70001; "Sales Code"; Code[20]) { }
+        field(80000; "Far Away"; Integer) { }
+    }
+}
+
+enumextension 50101 "Status Ext" extends "Sales Status"
+{
+    value(50101; Approved) { }
+    value(99; Legacy) { }
+}
+
+interface "ISales"
+{
+}
+`,
+		// Legacy single idRange
+		"legacy/app.json":      `{"id": "2", "name": "Legacy", "idRange": {"from": 60000, "to": 60099}}`,
+		"legacy/src/Legacy.al": "codeunit 60000 \"Legacy\"\n{\n}\n\ncodeunit 60100 \"Legacy Out\"\n{\n}\n",
+		"norange/app.json":     `{"id": "3", "name": "No Ranges"}`,
+		"norange/src/Page.al":  "page 50100 \"Anything\"\n{\n}\n",
+		"scripts/Helper.al":    "codeunit 1 \"Helper\"\n{\n}\n",
+	})
+
+	result, err := scanner.ScanDirectory(root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		file, prefix string
+		line, column int
+	}{
+		{"legacy/src/Legacy.al", `codeunit 60100 "Legacy Out" is outside the app's ID ranges (60000..60099)`, 5, 1},
+		{"main/src/Objects.al", `codeunit 60000 "Out Of Range" is outside the app's ID ranges (50100..50149, 70000..70009)`, 5, 1},
+		{"main/src/Objects.al", `field 80000 "Far Away" in tableextension "Customer Ext"`, 14, 9},
+		{"main/src/Objects.al", `value 99 "Legacy" in enumextension "Status Ext"`, 21, 5},
+		{"norange/app.json", "app.json declares no idRanges; 1 object(s) were not checked", 0, 0},
+	}
+	if report.TotalFindings != len(expected) {
+		t.Fatalf("expected %d findings, got %d: %+v", len(expected), report.TotalFindings, report.Findings)
+	}
+	for i, exp := range expected {
+		f := report.Findings[i]
+		if f.File != filepath.Join(root, exp.file) || !strings.HasPrefix(f.Message, exp.prefix) || f.Line != exp.line || f.Column != exp.column {
+			t.Errorf("finding %d: expected %s:%d:%d %q, got %s", i, exp.file, exp.line, exp.column, exp.prefix, f)
+		}
+	}
+
+	if report.GetCountByRule(RuleIDRange) != 4 || report.GetCountByRule(RuleNoIDRanges) != 1 {
+		t.Errorf("unexpected counts by rule %+v", report.CountsByRule)
+	}
+	if member := report.Findings[2]; member.MemberKind != scanner.MemberField || member.MemberID != "80000" || member.ObjectID != "70000" {
+		t.Errorf("unexpected member finding %+v", member)
+	}
+}
+
+func TestRunDuplicates(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"app.json":     `{"id": "1", "name": "Sales", "idRanges": [{"from": 50100, "to": 50149}]}`,
+		"src/Post.al":  "codeunit 50100 \"Post Sales\"\n{\n}\n",
+		"src/Other.al": "codeunit 50100 \"Release Sales\"\n{\n}\n\ncodeunit 50101 \"post sales\"\n{\n}\n",
+		// Extensions only clash with their own type
+		"src/Ext.al": "tableextension 50100 \"Post Sales\" extends Customer\n{\n}\n",
+	})
+
+	result, err := scanner.ScanDirectory(root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.GetCountByRule(RuleDuplicateID) != 2 || report.GetCountByRule(RuleDuplicateName) != 2 || report.TotalFindings != 4 {
+		t.Fatalf("unexpected counts by rule %+v: %+v", report.CountsByRule, report.Findings)
+	}
+	first := report.Findings[0]
+	expected := `codeunit 50100 "Release Sales" reuses the codeunit ID 50100, also declared at ` + filepath.Join(root, "src/Post.al") + ":1"
+	if first.File != filepath.Join(root, "src/Other.al") || first.Line != 1 || first.Message != expected {
+		t.Errorf("unexpected finding %s", first)
+	}
+	if first.App != "Sales" {
+		t.Errorf("expected app Sales, got %q", first.App)
+	}
+}
+
+func TestRunReservations(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"app.json": `{"id": "1", "name": "Sales", "idRanges": [{"from": 50100, "to": 50149}]}`,
+		".objectids.json": `{
+  "reservations": [
+    {"type":"codeunit","id":50100,"name":"Post Sales","by":"alice"},
+    {"type":"codeunit","id":50101,"name":"Release Sales","by":"bob"},
+    {"type":"codeunit","id":50102,"name":"Archive","by":"alice"},
+    {"type":"codeunit","id":50102,"name":"Print","by":"bob"},
+    {"type":"field","id":50100,"table":"Customer","name":"Sales Code"}
+  ]
+}
+`,
+		"src/Objects.al": `codeunit 50100 "Post Sales"
+{
+}
+
+codeunit 50101 "Ship Sales"
+{
+}
+
+tableextension 50100 "Customer Ext" extends Customer
+{
+    fields
+    {
+        field(50100; "Sales Group"; Code[20]) { }
+    }
+}
+`,
+	})
+
+	result, err := scanner.ScanDirectory(root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		filepath.Join(root, ".objectids.json") + `: duplicate-id: codeunit 50102 is reserved 2 times: "Archive" by alice, "Print" by bob`,
+		filepath.Join(root, "src/Objects.al") + `:5:1: reserved-id: codeunit 50101 "Ship Sales" uses an ID reserved for "Release Sales" by bob`,
+		filepath.Join(root, "src/Objects.al") + `:13:9: reserved-id: field 50100 "Sales Group" in tableextension "Customer Ext" uses an ID reserved for "Sales Code"`,
+	}
+	if report.TotalFindings != len(expected) {
+		t.Fatalf("expected %d findings, got %d: %+v", len(expected), report.TotalFindings, report.Findings)
+	}
+	for i, exp := range expected {
+		if got := report.Findings[i].String(); got != exp {
+			t.Errorf("finding %d: expected %q, got %q", i, exp, got)
+		}
+	}
+
+	testutil.WriteFiles(t, root, map[string]string{".objectids.json": "{"})
+	if _, err := Run(result); err == nil {
+		t.Error("expected an error for an invalid ledger")
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	tests := []struct {
+		finding  Finding
+		expected string
+	}{
+		{Finding{Rule: RuleIDRange, Message: "out of range", File: "a.al", Line: 3, Column: 5}, "a.al:3:5: id-range: out of range"},
+		{Finding{Rule: RuleNoIDRanges, Message: "no ranges", File: "app.json"}, "app.json: no-id-ranges: no ranges"},
+	}
+	for _, tt := range tests {
+		if got := tt.finding.String(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package conflict finds object IDs and names introduced on two branches
+// since their merge base that would collide once the branches are merged.
+package conflict
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// Side is one of the two revisions compared.
+type Side struct {
+	Ref    string `json:"ref"`
+	Commit string `json:"commit"`
+	// Added is the number of objects introduced on this side since the
+	// merge base.
+	Added int `json:"added"`
+}
+
+// Collision is an object ID or name introduced on both sides for different
+// objects. Kind is counter.DuplicateID or counter.DuplicateName.
+type Collision struct {
+	Kind   string             `json:"kind"`
+	App    scanner.AppInfo    `json:"app"`
+	Type   string             `json:"type"`
+	Value  string             `json:"value"`
+	Ours   []scanner.BCObject `json:"ours"`
+	Theirs []scanner.BCObject `json:"theirs"`
+}
+
+// Report is the result of comparing two revisions.
+type Report struct {
+	Repository string `json:"repository"`
+	// MergeBase is empty when the revisions share no history.
+	MergeBase       string      `json:"mergeBase,omitempty"`
+	Ours            Side        `json:"ours"`
+	Theirs          Side        `json:"theirs"`
+	TotalCollisions int         `json:"totalCollisions"`
+	Collisions      []Collision `json:"collisions"`
+}
+
+// objectKey identifies an object declaration across revisions.
+type objectKey struct {
+	app     string
+	objType string
+	id      string
+	name    string
+}
+
+// appKey identifies an app across revisions, whose version and even name may
+// differ between branches: by its ID, or its name if it has no ID.
+func appKey(obj scanner.BCObject) string {
+	if obj.App == nil {
+		return ""
+	}
+	if obj.App.ID != "" {
+		return strings.ToLower(obj.App.ID)
+	}
+	return strings.ToLower(obj.App.Name)
+}
+
+// keyOf returns the key of an object; names compare case-insensitively.
+func keyOf(obj scanner.BCObject) objectKey {
+	return objectKey{app: appKey(obj), objType: obj.Type, id: obj.ID, name: strings.ToLower(obj.Name)}
+}
+
+// Added returns the objects of side that the merge base does not declare with
+// the same app, type, ID and name: new objects, and renamed or renumbered
+// ones. Objects read from .app packages or C/AL exports are ignored.
+func Added(base, side []scanner.BCObject) []scanner.BCObject {
+	existing := make(map[objectKey]bool)
+	for _, obj := range base {
+		existing[keyOf(obj)] = true
+	}
+
+	var added []scanner.BCObject
+	for _, obj := range side {
+		if !obj.FromSource() {
+			continue
+		}
+		if !existing[keyOf(obj)] {
+			added = append(added, obj)
+		}
+	}
+	return added
+}
+
+// Compare returns the collisions between the objects added on each side.
+// Like counter.FindDuplicates, IDs collide per object type and names per
+// object type and namespace, case-insensitively, within the same app. An
+// object added identically on both sides, e.g. by a cherry-pick, does not
+// collide with itself.
+func Compare(ours, theirs []scanner.BCObject) []Collision {
+	same := make(map[objectKey]bool)
+	inOurs := make(map[objectKey]bool)
+	for _, obj := range ours {
+		inOurs[keyOf(obj)] = true
+	}
+	for _, obj := range theirs {
+		if inOurs[keyOf(obj)] {
+			same[keyOf(obj)] = true
+		}
+	}
+
+	type groupKey struct {
+		kind    string
+		app     string
+		objType string
+		value   string
+	}
+	keysOf := func(obj scanner.BCObject) []groupKey {
+		var keys []groupKey
+		if obj.ID != "" {
+			keys = append(keys, groupKey{counter.DuplicateID, appKey(obj), obj.Type, obj.ID})
+		}
+		if obj.Name != "" {
+			keys = append(keys, groupKey{counter.DuplicateName, appKey(obj), obj.Type, strings.ToLower(obj.Namespace) + "\x00" + strings.ToLower(obj.Name)})
+		}
+		return keys
+	}
+
+	var order []groupKey
+	groups := make(map[groupKey]*Collision)
+	for _, obj := range ours {
+		if same[keyOf(obj)] {
+			continue
+		}
+		for _, k := range keysOf(obj) {
+			c, ok := groups[k]
+			if !ok {
+				c = &Collision{Kind: k.kind, Type: obj.Type, Value: obj.ID}
+				if obj.App != nil {
+					c.App = *obj.App
+				}
+				if k.kind == counter.DuplicateName {
+					c.Value = obj.Name
+				}
+				groups[k] = c
+				order = append(order, k)
+			}
+			c.Ours = append(c.Ours, obj)
+		}
+	}
+	for _, obj := range theirs {
+		if same[keyOf(obj)] {
+			continue
+		}
+		for _, k := range keysOf(obj) {
+			if c, ok := groups[k]; ok {
+				c.Theirs = append(c.Theirs, obj)
+			}
+		}
+	}
+
+	var collisions []Collision
+	for _, k := range order {
+		if c := groups[k]; len(c.Theirs) > 0 {
+			collisions = append(collisions, *c)
+		}
+	}
+
+	// Sort by kind (IDs first), type and value (numerically for IDs)
+	sort.SliceStable(collisions, func(i, j int) bool {
+		a, b := collisions[i], collisions[j]
+		if a.Kind != b.Kind {
+			return a.Kind == counter.DuplicateID
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Kind == counter.DuplicateID {
+			if ai, bi := scanner.NumericID(a.Value), scanner.NumericID(b.Value); ai != bi {
+				return ai < bi
+			}
+		}
+		return strings.ToLower(a.Value) < strings.ToLower(b.Value)
+	})
+	return collisions
+}
@@ -0,0 +1,186 @@
+package conflict
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/andrijan/bc-objects-counter/internal/testutil"
+)
+
+func TestAdded(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales"}
+	base := []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post", App: sales},
+		{Type: "table", ID: "50100", Name: "Setup", App: sales},
+	}
+	side := []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "POST", App: &scanner.AppInfo{ID: "1", Name: "Sales", Version: "2.0.0.0"}},
+		{Type: "table", ID: "50100", Name: "Sales Setup", App: sales},
+		{Type: "codeunit", ID: "50101", Name: "Release", App: sales},
+		{Type: "codeunit", ID: "50102", Name: "Dependency", FilePath: "Base.app", App: sales},
+		{Type: "codeunit", ID: "50103", Name: "Legacy", CAL: &scanner.CALProperties{}, App: sales},
+	}
+
+	var names []string
+	for _, obj := range Added(base, side) {
+		names = append(names, obj.Name)
+	}
+	if strings.Join(names, ",") != "Sales Setup,Release" {
+		t.Errorf("expected the renamed and new objects, got %v", names)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales"}
+	service := &scanner.AppInfo{ID: "2", Name: "Service"}
+
+	tests := []struct {
+		name     string
+		ours     []scanner.BCObject
+		theirs   []scanner.BCObject
+		expected []string
+	}{
+		{
+			name:     "same ID",
+			ours:     []scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Release", App: sales}},
+			theirs:   []scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Archive", App: sales}},
+			expected: []string{"id codeunit 50101"},
+		},
+		{
+			name:     "same name",
+			ours:     []scanner.BCObject{{Type: "table", ID: "50100", Name: "Setup", App: sales}},
+			theirs:   []scanner.BCObject{{Type: "table", ID: "50101", Name: "SETUP", App: sales}},
+			expected: []string{"name table Setup"},
+		},
+		{
+			name:   "different namespaces",
+			ours:   []scanner.BCObject{{Type: "table", ID: "50100", Name: "Setup", Namespace: "Sales", App: sales}},
+			theirs: []scanner.BCObject{{Type: "table", ID: "50101", Name: "Setup", Namespace: "Service", App: sales}},
+		},
+		{
+			name:   "different types",
+			ours:   []scanner.BCObject{{Type: "table", ID: "50100", Name: "Setup", App: sales}},
+			theirs: []scanner.BCObject{{Type: "page", ID: "50100", Name: "Setup", App: sales}},
+		},
+		{
+			name:   "different apps",
+			ours:   []scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Release", App: sales}},
+			theirs: []scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Release", App: service}},
+		},
+		{
+			name:   "added identically on both sides",
+			ours:   []scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Release", App: sales}},
+			theirs: []scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Release", App: sales}},
+		},
+		{
+			name: "sorted IDs first, numerically",
+			ours: []scanner.BCObject{
+				{Type: "codeunit", ID: "50110", Name: "Ten", App: sales},
+				{Type: "codeunit", ID: "50102", Name: "Two", App: sales},
+			},
+			theirs: []scanner.BCObject{
+				{Type: "codeunit", ID: "50120", Name: "Ten", App: sales},
+				{Type: "codeunit", ID: "50110", Name: "Other", App: sales},
+				{Type: "codeunit", ID: "50102", Name: "Another", App: sales},
+			},
+			expected: []string{"id codeunit 50102", "id codeunit 50110", "name codeunit Ten"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range Compare(tt.ours, tt.theirs) {
+				got = append(got, c.Kind+" "+c.Type+" "+c.Value)
+				if len(c.Ours) == 0 || len(c.Theirs) == 0 {
+					t.Errorf("%s %s: expected objects on both sides", c.Kind, c.Value)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// git runs the git command line tool in dir to build test repositories.
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func commit(t *testing.T, dir, message string, files map[string]string) {
+	t.Helper()
+	testutil.WriteFiles(t, dir, files)
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", message)
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	commit(t, dir, "base", map[string]string{
+		"app.json":    `{"id": "1", "name": "Sales", "idRanges": [{"from": 50100, "to": 50149}]}`,
+		"src/Post.al": "codeunit 50100 \"Post\"\n{\n}\n",
+	})
+	git(t, dir, "checkout", "-q", "-b", "feature")
+	commit(t, dir, "feature", map[string]string{
+		"src/Release.al": "codeunit 50101 \"Release\"\n{\n}\n\ntable 50100 \"Setup\"\n{\n}\n",
+	})
+	git(t, dir, "checkout", "-q", "main")
+	commit(t, dir, "main", map[string]string{
+		"src/Archive.al": "codeunit 50101 \"Archive\"\n{\n}\n\ntable 50101 \"Setup\"\n{\n}\n",
+	})
+	// The working tree must not be scanned
+	if err := os.WriteFile(filepath.Join(dir, "src", "Draft.al"), []byte("codeunit 50101 \"Draft\"\n{\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), dir, "feature", "main", scanner.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.MergeBase == "" {
+		t.Error("expected a merge base")
+	}
+	if report.Ours.Added != 2 || report.Theirs.Added != 2 {
+		t.Errorf("expected 2 objects added on each side, got %d and %d", report.Ours.Added, report.Theirs.Added)
+	}
+	if report.TotalCollisions != 2 {
+		t.Fatalf("expected 2 collisions, got %d: %+v", report.TotalCollisions, report.Collisions)
+	}
+
+	id, name := report.Collisions[0], report.Collisions[1]
+	if id.Kind != counter.DuplicateID || id.Value != "50101" || id.App.Name != "Sales" {
+		t.Errorf("unexpected ID collision %+v", id)
+	}
+	if id.Ours[0].FilePath != "src/Release.al" || id.Theirs[0].Name != "Archive" {
+		t.Errorf("unexpected colliding objects %+v, %+v", id.Ours, id.Theirs)
+	}
+	if name.Kind != counter.DuplicateName || name.Value != "Setup" || name.Theirs[0].Line != 5 {
+		t.Errorf("unexpected name collision %+v", name)
+	}
+
+	if _, err := Run(context.Background(), dir, "feature", "missing", scanner.Options{}); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
@@ -0,0 +1,89 @@
+package conflict
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/gitrepo"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// Run compares the revisions ours and theirs of the git repository at
+// repoPath. The AL sources and app.json files of both revisions and of their
+// merge base are read from the repository's object database and scanned;
+// the working tree is not used. Sources are scanned with opts.Jobs workers and
+// the preprocessor symbols in opts.Defines.
+func Run(ctx context.Context, repoPath, ours, theirs string, opts scanner.Options) (*Report, error) {
+	repo, err := gitrepo.Open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Close()
+
+	oursHash, err := repo.Resolve(ours)
+	if err != nil {
+		return nil, err
+	}
+	theirsHash, err := repo.Resolve(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Repository: repoPath,
+		Ours:       Side{Ref: ours, Commit: oursHash.String()},
+		Theirs:     Side{Ref: theirs, Commit: theirsHash.String()},
+	}
+
+	var baseObjects []scanner.BCObject
+	base, ok, err := repo.MergeBase(oursHash, theirsHash)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		report.MergeBase = base.String()
+		if baseObjects, err = scanCommit(ctx, repo, base, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	oursObjects, err := scanCommit(ctx, repo, oursHash, opts)
+	if err != nil {
+		return nil, err
+	}
+	theirsObjects, err := scanCommit(ctx, repo, theirsHash, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	oursAdded := Added(baseObjects, oursObjects)
+	theirsAdded := Added(baseObjects, theirsObjects)
+	report.Ours.Added = len(oursAdded)
+	report.Theirs.Added = len(theirsAdded)
+	report.Collisions = Compare(oursAdded, theirsAdded)
+	report.TotalCollisions = len(report.Collisions)
+	return report, nil
+}
+
+// scanCommit scans the AL sources and app.json files in the tree of commit.
+func scanCommit(ctx context.Context, repo *gitrepo.Repository, commit gitrepo.Hash, opts scanner.Options) ([]scanner.BCObject, error) {
+	files, err := repo.Files(commit, func(p string) bool {
+		name := strings.ToLower(path.Base(p))
+		return strings.HasSuffix(name, ".al") || name == "app.json"
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", commit.Short(), err)
+	}
+
+	sources := make([]scanner.Source, len(files))
+	for i, f := range files {
+		sources[i] = scanner.Source{Path: f.Path, Data: f.Data}
+	}
+	result, err := scanner.ScanSources(ctx, sources, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Objects, nil
+}
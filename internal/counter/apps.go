@@ -0,0 +1,78 @@
+package counter
+
+import (
+	"sort"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// AppSummary breaks down the objects of one app by type. App is the zero
+// value for objects that do not belong to any app.
+type AppSummary struct {
+	App          scanner.AppInfo    `json:"app"`
+	TotalObjects int                `json:"totalObjects"`
+	CountsByType []ObjectCount      `json:"countsByType"`
+	Lines        scanner.LineCounts `json:"lines"`
+}
+
+// GroupByApp groups objects by the app they belong to, sorted by app name,
+// version and ID, with objects outside any app last. It returns nil when no
+// object belongs to an app.
+func GroupByApp(objects []scanner.BCObject) []AppSummary {
+	var hasApps bool
+	for _, obj := range objects {
+		if obj.App != nil {
+			hasApps = true
+			break
+		}
+	}
+	if !hasApps {
+		return nil
+	}
+
+	var order []scanner.AppInfo
+	typeCounts := make(map[scanner.AppInfo]map[string]int)
+	lines := make(map[scanner.AppInfo]scanner.LineCounts)
+	for _, obj := range objects {
+		var app scanner.AppInfo
+		if obj.App != nil {
+			app = *obj.App
+		}
+		if _, ok := typeCounts[app]; !ok {
+			typeCounts[app] = make(map[string]int)
+			order = append(order, app)
+		}
+		typeCounts[app][obj.Type]++
+		l := lines[app]
+		l.Add(obj.Lines)
+		lines[app] = l
+	}
+
+	var apps []AppSummary
+	for _, app := range order {
+		summary := AppSummary{
+			App:          app,
+			CountsByType: sortedTypeCounts(typeCounts[app]),
+			Lines:        lines[app],
+		}
+		for _, c := range summary.CountsByType {
+			summary.TotalObjects += c.Count
+		}
+		apps = append(apps, summary)
+	}
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		a, b := apps[i].App, apps[j].App
+		if (a == scanner.AppInfo{}) != (b == scanner.AppInfo{}) {
+			return b == scanner.AppInfo{}
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.ID < b.ID
+	})
+	return apps
+}
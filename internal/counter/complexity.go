@@ -0,0 +1,140 @@
+package counter
+
+import (
+	"sort"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// DefaultTopComplex is the number of routines listed as most complex unless
+// the summary is told otherwise with TopComplex.
+const DefaultTopComplex = 10
+
+// RoutineComplexity describes the complexity of a procedure or trigger.
+type RoutineComplexity struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+	ObjectName string `json:"objectName"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Complexity int    `json:"complexity"`
+	Nesting    int    `json:"nesting"`
+	FilePath   string `json:"filePath"`
+	Line       int    `json:"line"`
+}
+
+// ObjectComplexity rolls up the complexity of the routines of one object.
+type ObjectComplexity struct {
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Routines   int    `json:"routines"`
+	Total      int    `json:"total"`
+	Max        int    `json:"max"`
+	MaxNesting int    `json:"maxNesting"`
+	FilePath   string `json:"filePath"`
+}
+
+// ComplexRoutines returns every procedure and trigger with a body, most
+// complex first.
+func ComplexRoutines(objects []scanner.BCObject) []RoutineComplexity {
+	var routines []RoutineComplexity
+	for _, obj := range objects {
+		for _, m := range obj.Members {
+			if m.Complexity == 0 {
+				continue
+			}
+			routines = append(routines, RoutineComplexity{
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+				Kind:       m.Kind,
+				Name:       m.Name,
+				Complexity: m.Complexity,
+				Nesting:    m.Nesting,
+				FilePath:   obj.FilePath,
+				Line:       m.Line,
+			})
+		}
+	}
+
+	// Sort by complexity, then nesting (descending), then by location
+	sort.SliceStable(routines, func(i, j int) bool {
+		a, b := routines[i], routines[j]
+		if a.Complexity != b.Complexity {
+			return a.Complexity > b.Complexity
+		}
+		if a.Nesting != b.Nesting {
+			return a.Nesting > b.Nesting
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+	return routines
+}
+
+// summarizeComplexity rolls up routine complexity per object and lists the
+// most complex routines.
+func summarizeComplexity(summary *Summary) {
+	for _, obj := range summary.Objects {
+		oc := ObjectComplexity{Type: obj.Type, ID: obj.ID, Name: obj.Name, FilePath: obj.FilePath}
+		for _, m := range obj.Members {
+			if m.Complexity == 0 {
+				continue
+			}
+			oc.Routines++
+			oc.Total += m.Complexity
+			oc.Max = max(oc.Max, m.Complexity)
+			oc.MaxNesting = max(oc.MaxNesting, m.Nesting)
+		}
+		if oc.Routines > 0 {
+			summary.ComplexityByObject = append(summary.ComplexityByObject, oc)
+		}
+	}
+
+	// Sort by most complex routine, then by total complexity (descending)
+	sort.SliceStable(summary.ComplexityByObject, func(i, j int) bool {
+		a, b := summary.ComplexityByObject[i], summary.ComplexityByObject[j]
+		if a.Max != b.Max {
+			return a.Max > b.Max
+		}
+		return a.Total > b.Total
+	})
+
+	summary.TopComplex(DefaultTopComplex)
+}
+
+// TopComplex lists the n most complex routines in MostComplex.
+func (s *Summary) TopComplex(n int) {
+	routines := ComplexRoutines(s.Objects)
+	if n >= 0 && len(routines) > n {
+		routines = routines[:n]
+	}
+	s.MostComplex = routines
+}
+
+// ExceedingComplexity returns the routines whose complexity is above limit,
+// most complex first.
+func (s *Summary) ExceedingComplexity(limit int) []RoutineComplexity {
+	var result []RoutineComplexity
+	for _, r := range ComplexRoutines(s.Objects) {
+		if r.Complexity <= limit {
+			break
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// GetObjectComplexity returns the complexity rollup of an object, if it has
+// any routines.
+func (s *Summary) GetObjectComplexity(objType, id, name string) (ObjectComplexity, bool) {
+	for _, oc := range s.ComplexityByObject {
+		if oc.Type == objType && oc.ID == id && oc.Name == name {
+			return oc, true
+		}
+	}
+	return ObjectComplexity{}, false
+}
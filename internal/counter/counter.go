@@ -3,6 +3,7 @@ package counter
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/andrijan/bc-objects-counter/internal/scanner"
 )
@@ -13,12 +14,87 @@ type ObjectCount struct {
 	Count int    `json:"count"`
 }
 
+// NamespaceCount represents the number of objects declared in a namespace.
+// Objects declared outside any namespace are counted under an empty Namespace.
+type NamespaceCount struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
+// TargetCount represents how many extension objects of a type extend a given base object.
+type TargetCount struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	Count  int    `json:"count"`
+}
+
+// InterfaceImplementations lists the objects implementing a given interface.
+type InterfaceImplementations struct {
+	Interface    string             `json:"interface"`
+	Count        int                `json:"count"`
+	Implementers []scanner.BCObject `json:"implementers"`
+}
+
+// PropertyCount represents the number of objects with a given value of a
+// property. Objects that do not set the property are counted under an empty Value.
+type PropertyCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PropertyBreakdown counts objects by the value of one property, e.g. PageType.
+type PropertyBreakdown struct {
+	Property string          `json:"property"`
+	Counts   []PropertyCount `json:"counts"`
+}
+
 // Summary contains the aggregated results of scanning BC objects.
 type Summary struct {
-	TotalObjects  int                           `json:"totalObjects"`
-	CountsByType  []ObjectCount                 `json:"countsByType"`
-	Objects       []scanner.BCObject            `json:"objects"`
-	ObjectsByType map[string][]scanner.BCObject `json:"objectsByType"`
+	// Root is the directory that was scanned, if known. Snapshots compare
+	// object file paths relative to it.
+	Root              string                        `json:"root,omitempty"`
+	TotalObjects      int                           `json:"totalObjects"`
+	CountsByType      []ObjectCount                 `json:"countsByType"`
+	CountsByNamespace []NamespaceCount              `json:"countsByNamespace"`
+	ExtendsTargets    []TargetCount                 `json:"extendsTargets"`
+	Implementations   []InterfaceImplementations    `json:"implementations"`
+	Objects           []scanner.BCObject            `json:"objects"`
+	ObjectsByType     map[string][]scanner.BCObject `json:"objectsByType"`
+	// Lines totals the line counts of all scanned files, or of all objects
+	// when the summary was built from objects alone.
+	Lines       scanner.LineCounts    `json:"lines"`
+	LinesByType []TypeLines           `json:"linesByType"`
+	Files       []scanner.FileMetrics `json:"files,omitempty"`
+	// TotalMembers counts the fields, keys, procedures, ... of all objects.
+	TotalMembers       int           `json:"totalMembers"`
+	CountsByMemberKind []MemberCount `json:"countsByMemberKind"`
+	MembersByType      []TypeMembers `json:"membersByType"`
+	// Apps breaks the objects down by the app they belong to. It is only set
+	// when at least one object was attributed to an app.
+	Apps []AppSummary `json:"apps,omitempty"`
+	// Duplicates lists object IDs and names declared more than once per app.
+	Duplicates []Duplicate `json:"duplicates,omitempty"`
+	// ComplexityByObject rolls up routine complexity per object, most complex
+	// first; MostComplex lists the top routines (see TopComplex).
+	ComplexityByObject []ObjectComplexity  `json:"complexityByObject,omitempty"`
+	MostComplex        []RoutineComplexity `json:"mostComplex,omitempty"`
+	// Obsolete groups the obsolete objects and members by ObsoleteTag.
+	Obsolete []ObsoleteGroup `json:"obsolete,omitempty"`
+	// ByProperty is set when the summary is grouped by a property (--group-by).
+	ByProperty *PropertyBreakdown `json:"byProperty,omitempty"`
+	// InactiveObjects are declared in excluded #if branches and not counted.
+	InactiveObjects []scanner.BCObject   `json:"inactiveObjects,omitempty"`
+	Diagnostics     []scanner.Diagnostic `json:"diagnostics"`
+}
+
+// Summarize aggregates a scan result into a summary, carrying over its file
+// metrics, inactive objects and diagnostics.
+func Summarize(result *scanner.Result) *Summary {
+	summary := CountObjects(result.Objects)
+	summary.setFiles(result.Files)
+	summary.InactiveObjects = result.InactiveObjects
+	summary.Diagnostics = result.Diagnostics
+	return summary
 }
 
 // CountObjects aggregates the scanned BC objects into a summary.
@@ -29,37 +105,191 @@ func CountObjects(objects []scanner.BCObject) *Summary {
 		ObjectsByType: make(map[string][]scanner.BCObject),
 	}
 
-	// Group objects by type
+	// Group objects by type, extension target and implemented interface.
+	// Targets and interfaces are AL identifiers and compare
+	// case-insensitively, like GetExtensionsOf and GetImplementers; each is
+	// reported under its first spelling.
 	typeCounts := make(map[string]int)
+	namespaceCounts := make(map[string]int)
+	targetCounts := make(map[TargetCount]int)
+	implementers := make(map[string][]scanner.BCObject)
+	targetSpelling := make(map[string]string)
+	interfaceSpelling := make(map[string]string)
 	for _, obj := range objects {
 		typeCounts[obj.Type]++
+		namespaceCounts[obj.Namespace]++
 		summary.ObjectsByType[obj.Type] = append(summary.ObjectsByType[obj.Type], obj)
+
+		if obj.Extends != "" {
+			targetCounts[TargetCount{Type: obj.Type, Target: firstSpelling(targetSpelling, obj.Extends)}]++
+		}
+		for _, iface := range obj.Implements {
+			iface = firstSpelling(interfaceSpelling, iface)
+			implementers[iface] = append(implementers[iface], obj)
+		}
 	}
 
-	// Convert to sorted slice
-	for objType, count := range typeCounts {
-		summary.CountsByType = append(summary.CountsByType, ObjectCount{
-			Type:  objType,
-			Count: count,
+	summary.CountsByType = sortedTypeCounts(typeCounts)
+
+	for ns, count := range namespaceCounts {
+		summary.CountsByNamespace = append(summary.CountsByNamespace, NamespaceCount{
+			Namespace: ns,
+			Count:     count,
 		})
 	}
 
-	// Sort by count (descending), then by type name
-	sort.Slice(summary.CountsByType, func(i, j int) bool {
-		if summary.CountsByType[i].Count != summary.CountsByType[j].Count {
-			return summary.CountsByType[i].Count > summary.CountsByType[j].Count
+	// Sort by namespace name so related namespaces stay together
+	sort.Slice(summary.CountsByNamespace, func(i, j int) bool {
+		return summary.CountsByNamespace[i].Namespace < summary.CountsByNamespace[j].Namespace
+	})
+
+	for key, count := range targetCounts {
+		key.Count = count
+		summary.ExtendsTargets = append(summary.ExtendsTargets, key)
+	}
+
+	// Sort by count (descending), then by type and target name
+	sort.Slice(summary.ExtendsTargets, func(i, j int) bool {
+		a, b := summary.ExtendsTargets[i], summary.ExtendsTargets[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
 		}
-		return summary.CountsByType[i].Type < summary.CountsByType[j].Type
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Target < b.Target
 	})
 
+	for iface, objs := range implementers {
+		summary.Implementations = append(summary.Implementations, InterfaceImplementations{
+			Interface:    iface,
+			Count:        len(objs),
+			Implementers: objs,
+		})
+	}
+
+	// Sort by number of implementers (descending), then by interface name
+	sort.Slice(summary.Implementations, func(i, j int) bool {
+		a, b := summary.Implementations[i], summary.Implementations[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Interface < b.Interface
+	})
+
+	summarizeLines(summary)
+	summarizeMembers(summary)
+	summarizeComplexity(summary)
+	summary.Apps = GroupByApp(objects)
+	summary.Duplicates = FindDuplicates(objects)
+
+	if groups := GroupObsolete(objects); len(groups) > 0 {
+		summary.Obsolete = groups
+	}
+
 	return summary
 }
 
+// firstSpelling returns the spelling name was first seen with, ignoring case,
+// recording it in spellings if it is new.
+func firstSpelling(spellings map[string]string, name string) string {
+	key := strings.ToLower(name)
+	if first, ok := spellings[key]; ok {
+		return first
+	}
+	spellings[key] = name
+	return name
+}
+
+// GroupByProperty groups objects by the value of the named property. Property
+// names and values are matched case-insensitively; each group is keyed by the
+// first spelling of its value, and objects without the property are grouped
+// under "".
+func GroupByProperty(objects []scanner.BCObject, property string) map[string][]scanner.BCObject {
+	groups := make(map[string][]scanner.BCObject)
+	spelling := make(map[string]string)
+	for _, obj := range objects {
+		value := obj.Properties.Get(property)
+		key, ok := spelling[strings.ToLower(value)]
+		if !ok {
+			key = value
+			spelling[strings.ToLower(value)] = key
+		}
+		groups[key] = append(groups[key], obj)
+	}
+	return groups
+}
+
+// CountByProperty counts objects by the value of the named property, sorted
+// by count (descending) and then by value.
+func CountByProperty(objects []scanner.BCObject, property string) []PropertyCount {
+	var counts []PropertyCount
+	for value, objs := range GroupByProperty(objects, property) {
+		counts = append(counts, PropertyCount{Value: value, Count: len(objs)})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
+
+// GroupBy sets ByProperty to a breakdown of the summary's objects by the named property.
+func (s *Summary) GroupBy(property string) {
+	s.ByProperty = &PropertyBreakdown{
+		Property: property,
+		Counts:   CountByProperty(s.Objects, property),
+	}
+}
+
+// GetExtensionsOf returns all extension objects whose extends target is the given base object.
+// A target without namespace also matches extensions naming it with its namespace.
+func (s *Summary) GetExtensionsOf(target string) []scanner.BCObject {
+	var result []scanner.BCObject
+	for _, obj := range s.Objects {
+		if obj.Extends == "" {
+			continue
+		}
+		if strings.EqualFold(obj.Extends, target) || strings.EqualFold(scanner.UnqualifiedName(obj.Extends), target) {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// GetImplementers returns all objects implementing the given interface.
+func (s *Summary) GetImplementers(iface string) []scanner.BCObject {
+	for _, impl := range s.Implementations {
+		if strings.EqualFold(impl.Interface, iface) {
+			return impl.Implementers
+		}
+	}
+	return nil
+}
+
 // GetObjectsByType returns all objects of a specific type from the summary.
 func (s *Summary) GetObjectsByType(objType string) []scanner.BCObject {
 	return s.ObjectsByType[objType]
 }
 
+// HasNamespaces reports whether any object in the summary is declared in a namespace.
+func (s *Summary) HasNamespaces() bool {
+	for _, c := range s.CountsByNamespace {
+		if c.Namespace != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// CountDiagnostics returns the number of diagnostics with the given severity.
+func (s *Summary) CountDiagnostics(severity scanner.Severity) int {
+	return scanner.CountDiagnostics(s.Diagnostics, severity)
+}
+
 // GetCountByType returns the count for a specific object type.
 func (s *Summary) GetCountByType(objType string) int {
 	for _, c := range s.CountsByType {
@@ -69,3 +299,23 @@ func (s *Summary) GetCountByType(objType string) int {
 	}
 	return 0
 }
+
+// sortedTypeCounts converts counts per object type to a slice sorted by count
+// (descending), then by type name.
+func sortedTypeCounts(typeCounts map[string]int) []ObjectCount {
+	var counts []ObjectCount
+	for objType, count := range typeCounts {
+		counts = append(counts, ObjectCount{
+			Type:  objType,
+			Count: count,
+		})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Type < counts[j].Type
+	})
+	return counts
+}
@@ -127,3 +127,390 @@ func TestCountObjectsSorting(t *testing.T) {
 		t.Errorf("expected third type to be table, got %s", summary.CountsByType[2].Type)
 	}
 }
+
+func TestCountObjectsExtendsAndImplements(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "tableextension", ID: "50100", Name: "Cust Ext 1", Extends: "Customer"},
+		{Type: "tableextension", ID: "50101", Name: "Cust Ext 2", Extends: "CUSTOMER"},
+		{Type: "pageextension", ID: "50100", Name: "Cust Card Ext", Extends: `Microsoft.Sales."Customer Card"`},
+		{Type: "codeunit", ID: "50100", Name: "Impl A", Implements: []string{"IShipping", "IPricing"}},
+		{Type: "enum", ID: "50100", Name: "Impl B", Implements: []string{"ishipping"}},
+	}
+
+	summary := CountObjects(objects)
+
+	if len(summary.ExtendsTargets) != 2 {
+		t.Fatalf("expected 2 extends targets, got %d", len(summary.ExtendsTargets))
+	}
+	first := summary.ExtendsTargets[0]
+	if first.Type != "tableextension" || first.Target != "Customer" || first.Count != 2 {
+		t.Errorf("expected tableextension Customer x2 first, got %+v", first)
+	}
+
+	if len(summary.Implementations) != 2 {
+		t.Fatalf("expected 2 interfaces, got %d", len(summary.Implementations))
+	}
+	if summary.Implementations[0].Interface != "IShipping" || summary.Implementations[0].Count != 2 {
+		t.Errorf("expected IShipping with 2 implementers first, got %+v", summary.Implementations[0])
+	}
+
+	if exts := summary.GetExtensionsOf("customer"); len(exts) != 2 {
+		t.Errorf("expected 2 extensions of Customer, got %d", len(exts))
+	}
+	if impls := summary.GetImplementers("IPricing"); len(impls) != 1 || impls[0].Name != "Impl A" {
+		t.Errorf("expected Impl A to implement IPricing, got %+v", impls)
+	}
+}
+
+func TestCountObjectsByNamespace(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Table 1", Namespace: "Contoso.Sales"},
+		{Type: "page", ID: "50100", Name: "Page 1", Namespace: "Contoso.Sales"},
+		{Type: "codeunit", ID: "50100", Name: "Codeunit 1", Namespace: "Contoso.Finance"},
+		{Type: "codeunit", ID: "50101", Name: "Codeunit 2"},
+	}
+
+	summary := CountObjects(objects)
+
+	expected := []NamespaceCount{
+		{Namespace: "", Count: 1},
+		{Namespace: "Contoso.Finance", Count: 1},
+		{Namespace: "Contoso.Sales", Count: 2},
+	}
+	if len(summary.CountsByNamespace) != len(expected) {
+		t.Fatalf("expected %d namespaces, got %d", len(expected), len(summary.CountsByNamespace))
+	}
+	for i, exp := range expected {
+		if summary.CountsByNamespace[i] != exp {
+			t.Errorf("namespace %d: expected %+v, got %+v", i, exp, summary.CountsByNamespace[i])
+		}
+	}
+	if !summary.HasNamespaces() {
+		t.Error("expected HasNamespaces to be true")
+	}
+}
+
+func TestSummarizeDiagnostics(t *testing.T) {
+	result := &scanner.Result{Findings: scanner.Findings{
+		Objects: []scanner.BCObject{
+			{Type: "table", ID: "50100", Name: "Table 1", FilePath: "file1.al"},
+		},
+		Diagnostics: []scanner.Diagnostic{
+			{File: "bad.al", Severity: scanner.SeverityError, Code: scanner.CodeReadError, Message: "permission denied"},
+			{File: "file1.al", Line: 3, Column: 5, Severity: scanner.SeverityWarning, Code: scanner.CodeLexError, Message: "unterminated string literal"},
+		},
+	}}
+
+	summary := Summarize(result)
+
+	if summary.TotalObjects != 1 {
+		t.Errorf("expected TotalObjects 1, got %d", summary.TotalObjects)
+	}
+	if count := summary.CountDiagnostics(scanner.SeverityError); count != 1 {
+		t.Errorf("expected 1 error, got %d", count)
+	}
+	if count := summary.CountDiagnostics(scanner.SeverityWarning); count != 1 {
+		t.Errorf("expected 1 warning, got %d", count)
+	}
+}
+
+func TestCountByProperty(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "page", ID: "50100", Name: "Customer API", Properties: scanner.Properties{"PageType": "API"}},
+		{Type: "page", ID: "50101", Name: "Vendor API", Properties: scanner.Properties{"pagetype": "api"}},
+		{Type: "page", ID: "50102", Name: "Setup", Properties: scanner.Properties{"PageType": "Card"}},
+		{Type: "codeunit", ID: "50100", Name: "Mgt"},
+	}
+
+	counts := CountByProperty(objects, "PageType")
+	expected := []PropertyCount{
+		{Value: "API", Count: 2},
+		{Value: "", Count: 1},
+		{Value: "Card", Count: 1},
+	}
+	if len(counts) != len(expected) {
+		t.Fatalf("expected %d values, got %d: %+v", len(expected), len(counts), counts)
+	}
+	for i, exp := range expected {
+		if counts[i] != exp {
+			t.Errorf("value %d: expected %+v, got %+v", i, exp, counts[i])
+		}
+	}
+
+	groups := GroupByProperty(objects, "pagetype")
+	if len(groups["API"]) != 2 || groups["API"][1].Name != "Vendor API" {
+		t.Errorf("expected both API pages in one group, got %+v", groups["API"])
+	}
+
+	summary := CountObjects(objects)
+	summary.GroupBy("PageType")
+	if summary.ByProperty == nil || summary.ByProperty.Property != "PageType" || len(summary.ByProperty.Counts) != 3 {
+		t.Errorf("unexpected breakdown %+v", summary.ByProperty)
+	}
+}
+
+func TestGroupObsolete(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Old Setup", FilePath: "setup.al", Line: 1,
+			Obsolete: &scanner.Obsolete{State: "Pending", Reason: "Replaced", Tag: "24.0"},
+			ObsoleteMembers: []scanner.ObsoleteMember{
+				{Kind: scanner.MemberField, ID: "2", Name: "Legacy Code", Line: 10,
+					Obsolete: scanner.Obsolete{State: "Removed", Tag: "9.0"}},
+			}},
+		{Type: "codeunit", ID: "50101", Name: "Mgt", FilePath: "mgt.al",
+			ObsoleteMembers: []scanner.ObsoleteMember{
+				{Kind: scanner.MemberProcedure, Name: "OldProc", Line: 5, Obsolete: scanner.Obsolete{State: "Pending"}},
+				{Kind: scanner.MemberProcedure, Name: "OtherProc", Line: 9, Obsolete: scanner.Obsolete{State: "Pending", Tag: "24.0"}},
+			}},
+		{Type: "page", ID: "50102", Name: "Current"},
+	}
+
+	summary := CountObjects(objects)
+
+	expected := []struct {
+		tag              string
+		count, pend, rem int
+	}{
+		{"9.0", 1, 0, 1},
+		{"24.0", 2, 2, 0},
+		{"", 1, 1, 0},
+	}
+	if len(summary.Obsolete) != len(expected) {
+		t.Fatalf("expected %d tags, got %d: %+v", len(expected), len(summary.Obsolete), summary.Obsolete)
+	}
+	for i, exp := range expected {
+		g := summary.Obsolete[i]
+		if g.Tag != exp.tag || g.Count != exp.count || g.Pending != exp.pend || g.Removed != exp.rem {
+			t.Errorf("group %d: expected %+v, got tag %q count %d pending %d removed %d", i, exp, g.Tag, g.Count, g.Pending, g.Removed)
+		}
+	}
+
+	entry := summary.Obsolete[0].Entries[0]
+	if entry.ObjectName != "Old Setup" || entry.MemberName != "Legacy Code" || entry.Line != 10 || entry.FilePath != "setup.al" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if summary.Obsolete[1].Entries[0].Kind != ObsoleteKindObject {
+		t.Errorf("expected the object entry first, got %+v", summary.Obsolete[1].Entries[0])
+	}
+	if n := summary.CountObsolete(); n != 4 {
+		t.Errorf("expected 4 obsolete entries, got %d", n)
+	}
+	if CountObjects(objects[2:]).Obsolete != nil {
+		t.Error("expected no obsolete groups without obsolete objects")
+	}
+}
+
+func TestCountObjectsMembers(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Table 1", Members: []scanner.Member{
+			{Kind: scanner.MemberField, ID: "1", Name: "No."},
+			{Kind: scanner.MemberField, ID: "2", Name: "Name"},
+			{Kind: scanner.MemberKey, Name: "PK"},
+		}},
+		{Type: "table", ID: "50101", Name: "Table 2", Members: []scanner.Member{
+			{Kind: scanner.MemberField, ID: "1", Name: "Code"},
+		}},
+		{Type: "codeunit", ID: "50100", Name: "Codeunit 1", Members: []scanner.Member{
+			{Kind: scanner.MemberProcedure, Name: "Run", Access: "public"},
+		}},
+		{Type: "page", ID: "50100", Name: "Empty Page"},
+	}
+
+	summary := CountObjects(objects)
+
+	if summary.TotalMembers != 5 {
+		t.Errorf("expected 5 members, got %d", summary.TotalMembers)
+	}
+	if n := summary.GetMemberCount(scanner.MemberField); n != 3 {
+		t.Errorf("expected 3 fields, got %d", n)
+	}
+	expectedKinds := []MemberCount{{scanner.MemberField, 3}, {scanner.MemberKey, 1}, {scanner.MemberProcedure, 1}}
+	if len(summary.CountsByMemberKind) != len(expectedKinds) {
+		t.Fatalf("expected %d kinds, got %+v", len(expectedKinds), summary.CountsByMemberKind)
+	}
+	for i, exp := range expectedKinds {
+		if summary.CountsByMemberKind[i] != exp {
+			t.Errorf("kind %d: expected %+v, got %+v", i, exp, summary.CountsByMemberKind[i])
+		}
+	}
+
+	if len(summary.MembersByType) != 2 || summary.MembersByType[0].Type != "table" || summary.MembersByType[0].Total != 4 {
+		t.Errorf("unexpected per-type members %+v", summary.MembersByType)
+	}
+	if tm := summary.GetTypeMembers("page"); tm != nil {
+		t.Errorf("expected no member totals for pages, got %+v", tm)
+	}
+}
+
+func TestSummarizeLines(t *testing.T) {
+	result := &scanner.Result{
+		Findings: scanner.Findings{Objects: []scanner.BCObject{
+			{Type: "table", ID: "50100", Name: "Table 1", Lines: scanner.LineCounts{Physical: 10, Code: 8, Comment: 1, Blank: 1}},
+			{Type: "table", ID: "50101", Name: "Table 2", Lines: scanner.LineCounts{Physical: 5, Code: 5}},
+			{Type: "codeunit", ID: "50100", Name: "Codeunit 1", Lines: scanner.LineCounts{Physical: 20, Code: 15, Comment: 3, Blank: 2}},
+		}},
+		Files: []scanner.FileMetrics{
+			{Path: "tables.al", Lines: scanner.LineCounts{Physical: 17, Code: 13, Comment: 2, Blank: 2}},
+			{Path: "codeunit.al", Lines: scanner.LineCounts{Physical: 20, Code: 15, Comment: 3, Blank: 2}},
+		},
+	}
+
+	// Without files, totals come from the objects
+	summary := CountObjects(result.Objects)
+	if expected := (scanner.LineCounts{Physical: 35, Code: 28, Comment: 4, Blank: 3}); summary.Lines != expected {
+		t.Errorf("expected object totals %+v, got %+v", expected, summary.Lines)
+	}
+
+	summary = Summarize(result)
+	if expected := (scanner.LineCounts{Physical: 37, Code: 28, Comment: 5, Blank: 4}); summary.Lines != expected {
+		t.Errorf("expected file totals %+v, got %+v", expected, summary.Lines)
+	}
+	if len(summary.Files) != 2 {
+		t.Errorf("expected 2 files, got %d", len(summary.Files))
+	}
+
+	if len(summary.LinesByType) != 2 || summary.LinesByType[0].Type != "codeunit" {
+		t.Errorf("expected codeunit first (most code lines), got %+v", summary.LinesByType)
+	}
+	if lines := summary.GetLinesByType("table"); lines.Code != 13 || lines.Physical != 15 {
+		t.Errorf("unexpected table lines %+v", lines)
+	}
+}
+
+func TestSummarizeComplexity(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Posting", FilePath: "a.al", Members: []scanner.Member{
+			{Kind: scanner.MemberProcedure, Name: "Post", Complexity: 12, Nesting: 3, Line: 10},
+			{Kind: scanner.MemberProcedure, Name: "Check", Complexity: 4, Nesting: 2, Line: 40},
+			{Kind: scanner.MemberProcedure, Name: "IPost", Line: 60},
+		}},
+		{Type: "table", ID: "50100", Name: "Setup", FilePath: "b.al", Members: []scanner.Member{
+			{Kind: scanner.MemberField, ID: "1", Name: "Primary Key"},
+			{Kind: scanner.MemberTrigger, Name: "OnInsert", Complexity: 4, Nesting: 1, Line: 20},
+		}},
+		{Type: "enum", ID: "50100", Name: "Status"},
+	}
+
+	summary := CountObjects(objects)
+	if len(summary.ComplexityByObject) != 2 {
+		t.Fatalf("expected 2 objects with routines, got %+v", summary.ComplexityByObject)
+	}
+	posting, ok := summary.GetObjectComplexity("codeunit", "50100", "Posting")
+	if !ok || posting.Routines != 2 || posting.Total != 16 || posting.Max != 12 || posting.MaxNesting != 3 {
+		t.Errorf("unexpected rollup for Posting: %+v", posting)
+	}
+	if summary.ComplexityByObject[0].Name != "Posting" {
+		t.Errorf("expected Posting to be the most complex object, got %s", summary.ComplexityByObject[0].Name)
+	}
+
+	expected := []string{"Post", "Check", "OnInsert"}
+	if len(summary.MostComplex) != len(expected) {
+		t.Fatalf("expected %d routines, got %+v", len(expected), summary.MostComplex)
+	}
+	for i, name := range expected {
+		if summary.MostComplex[i].Name != name {
+			t.Errorf("routine %d: expected %s, got %s", i, name, summary.MostComplex[i].Name)
+		}
+	}
+
+	summary.TopComplex(1)
+	if len(summary.MostComplex) != 1 || summary.MostComplex[0].ObjectName != "Posting" {
+		t.Errorf("expected only Post in the top 1, got %+v", summary.MostComplex)
+	}
+
+	if over := summary.ExceedingComplexity(4); len(over) != 1 || over[0].Name != "Post" {
+		t.Errorf("expected only Post above 4, got %+v", over)
+	}
+	if over := summary.ExceedingComplexity(12); len(over) != 0 {
+		t.Errorf("expected no routines above 12, got %+v", over)
+	}
+}
+
+func TestGroupByApp(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales", Publisher: "Contoso", Version: "1.0.0.0"}
+	tests := &scanner.AppInfo{ID: "2", Name: "Sales Tests", Publisher: "Contoso", Version: "1.0.0.0"}
+	objects := []scanner.BCObject{
+		{Type: "codeunit", ID: "50199", Name: "Helper", Lines: scanner.LineCounts{Physical: 3, Code: 3}},
+		{Type: "codeunit", ID: "50150", Name: "Sales Tests", App: tests},
+		{Type: "table", ID: "50100", Name: "Sales Setup", App: sales, Lines: scanner.LineCounts{Physical: 10, Code: 8, Blank: 2}},
+		{Type: "page", ID: "50100", Name: "Sales Setup", App: sales, Lines: scanner.LineCounts{Physical: 5, Code: 5}},
+		{Type: "table", ID: "50101", Name: "Sales Log", App: sales},
+	}
+
+	summary := CountObjects(objects)
+	if len(summary.Apps) != 3 {
+		t.Fatalf("expected 3 apps, got %+v", summary.Apps)
+	}
+
+	expected := []struct {
+		name  string
+		total int
+	}{
+		{"Sales", 3},
+		{"Sales Tests", 1},
+		{"", 1},
+	}
+	for i, exp := range expected {
+		app := summary.Apps[i]
+		if app.App.Name != exp.name || app.TotalObjects != exp.total {
+			t.Errorf("app %d: expected %q with %d objects, got %q with %d", i, exp.name, exp.total, app.App.Name, app.TotalObjects)
+		}
+	}
+
+	first := summary.Apps[0]
+	if len(first.CountsByType) != 2 || first.CountsByType[0] != (ObjectCount{Type: "table", Count: 2}) {
+		t.Errorf("unexpected counts by type %+v", first.CountsByType)
+	}
+	if first.Lines.Code != 13 {
+		t.Errorf("expected 13 code lines, got %d", first.Lines.Code)
+	}
+
+	if apps := GroupByApp([]scanner.BCObject{{Type: "table", ID: "1", Name: "T"}}); apps != nil {
+		t.Errorf("expected no app breakdown without apps, got %+v", apps)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales"}
+	other := &scanner.AppInfo{ID: "2", Name: "Other"}
+	objects := []scanner.BCObject{
+		{Type: "codeunit", ID: "50123", Name: "Post Sales", App: sales, FilePath: "a.al"},
+		{Type: "codeunit", ID: "50123", Name: "Release Sales", App: sales, FilePath: "b.al"},
+		// Same ID as the codeunits, but another type or app
+		{Type: "table", ID: "50123", Name: "Sales Log", App: sales, FilePath: "c.al"},
+		{Type: "codeunit", ID: "50123", Name: "Post Sales", App: other, FilePath: "d.al"},
+		// Extensions have their own ID space and may extend the same target
+		{Type: "tableextension", ID: "50123", Name: "Customer Ext", Extends: "Customer", App: sales, FilePath: "e.al"},
+		{Type: "tableextension", ID: "50124", Name: "customer ext", Extends: "Customer", App: sales, FilePath: "f.al"},
+		// Names only clash within the same namespace
+		{Type: "interface", Name: "ISales", Namespace: "Contoso.Sales", App: sales, FilePath: "g.al"},
+		{Type: "interface", Name: "ISales", Namespace: "Contoso.Purchase", App: sales, FilePath: "h.al"},
+		// Compiled packages are not checked
+		{Type: "codeunit", ID: "50123", Name: "Post Sales", App: sales, FilePath: "Sales.app"},
+	}
+
+	duplicates := FindDuplicates(objects)
+	expected := []struct {
+		kind, objType, value string
+		count                int
+	}{
+		{DuplicateID, "codeunit", "50123", 2},
+		{DuplicateName, "tableextension", "Customer Ext", 2},
+	}
+	if len(duplicates) != len(expected) {
+		t.Fatalf("expected %d duplicates, got %+v", len(expected), duplicates)
+	}
+	for i, exp := range expected {
+		d := duplicates[i]
+		if d.Kind != exp.kind || d.Type != exp.objType || d.Value != exp.value || len(d.Objects) != exp.count || d.App != *sales {
+			t.Errorf("duplicate %d: expected %+v, got %+v", i, exp, d)
+		}
+	}
+	if duplicates[0].Objects[1].FilePath != "b.al" {
+		t.Errorf("expected declarations in scan order, got %+v", duplicates[0].Objects)
+	}
+
+	if summary := CountObjects(objects); len(summary.Duplicates) != 2 {
+		t.Errorf("expected the summary to list 2 duplicates, got %d", len(summary.Duplicates))
+	}
+}
@@ -0,0 +1,99 @@
+package counter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// Kinds of duplicates.
+const (
+	DuplicateID   = "id"
+	DuplicateName = "name"
+)
+
+// Duplicate is an object ID or name declared more than once within an app.
+//
+// AL requires IDs to be unique per object type, so a tableextension may share
+// its ID with a table, but not with another tableextension. Names must be
+// unique per object type and namespace, compared case-insensitively. Objects
+// lists every declaration in scan order.
+type Duplicate struct {
+	Kind      string             `json:"kind"`
+	App       scanner.AppInfo    `json:"app"`
+	Type      string             `json:"type"`
+	Namespace string             `json:"namespace,omitempty"`
+	Value     string             `json:"value"`
+	Objects   []scanner.BCObject `json:"objects"`
+}
+
+// FindDuplicates returns the duplicate IDs and names among objects, grouped
+// per app, sorted by kind, type and value. Objects read from .app packages or
+// C/AL exports are ignored.
+func FindDuplicates(objects []scanner.BCObject) []Duplicate {
+	type key struct {
+		kind      string
+		app       scanner.AppInfo
+		objType   string
+		namespace string
+		value     string
+	}
+
+	var order []key
+	groups := make(map[key][]scanner.BCObject)
+	add := func(k key, obj scanner.BCObject) {
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], obj)
+	}
+
+	for _, obj := range objects {
+		if !obj.FromSource() {
+			continue
+		}
+		var app scanner.AppInfo
+		if obj.App != nil {
+			app = *obj.App
+		}
+		if obj.ID != "" {
+			add(key{kind: DuplicateID, app: app, objType: obj.Type, value: obj.ID}, obj)
+		}
+		if obj.Name != "" {
+			add(key{kind: DuplicateName, app: app, objType: obj.Type, namespace: strings.ToLower(obj.Namespace), value: strings.ToLower(obj.Name)}, obj)
+		}
+	}
+
+	var duplicates []Duplicate
+	for _, k := range order {
+		group := groups[k]
+		if len(group) < 2 {
+			continue
+		}
+		d := Duplicate{Kind: k.kind, App: k.app, Type: k.objType, Value: group[0].ID, Objects: group}
+		if k.kind == DuplicateName {
+			d.Namespace = group[0].Namespace
+			d.Value = group[0].Name
+		}
+		duplicates = append(duplicates, d)
+	}
+
+	// Sort by kind (IDs first), type and value (numerically for IDs)
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		a, b := duplicates[i], duplicates[j]
+		if a.Kind != b.Kind {
+			return a.Kind == DuplicateID
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Kind == DuplicateID {
+			if ai, bi := scanner.NumericID(a.Value), scanner.NumericID(b.Value); ai != bi {
+				return ai < bi
+			}
+		}
+		return strings.ToLower(a.Value) < strings.ToLower(b.Value)
+	})
+	return duplicates
+}
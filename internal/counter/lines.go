@@ -0,0 +1,59 @@
+package counter
+
+import (
+	"sort"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// TypeLines holds the summed line counts of all objects of one type.
+type TypeLines struct {
+	Type  string             `json:"type"`
+	Lines scanner.LineCounts `json:"lines"`
+}
+
+// summarizeLines fills in the line counts per object type and, until file
+// metrics are known, the overall totals from the objects.
+func summarizeLines(summary *Summary) {
+	summary.Lines = scanner.LineCounts{}
+	for objType, objects := range summary.ObjectsByType {
+		tl := TypeLines{Type: objType}
+		for _, obj := range objects {
+			tl.Lines.Add(obj.Lines)
+		}
+		summary.Lines.Add(tl.Lines)
+		summary.LinesByType = append(summary.LinesByType, tl)
+	}
+
+	// Sort by code lines (descending), then by type name
+	sort.Slice(summary.LinesByType, func(i, j int) bool {
+		a, b := summary.LinesByType[i], summary.LinesByType[j]
+		if a.Lines.Code != b.Lines.Code {
+			return a.Lines.Code > b.Lines.Code
+		}
+		return a.Type < b.Type
+	})
+}
+
+// setFiles records per-file line counts and makes the summary's totals the
+// sum over whole files, which includes lines outside any object.
+func (s *Summary) setFiles(files []scanner.FileMetrics) {
+	if len(files) == 0 {
+		return
+	}
+	s.Files = files
+	s.Lines = scanner.LineCounts{}
+	for _, f := range files {
+		s.Lines.Add(f.Lines)
+	}
+}
+
+// GetLinesByType returns the line counts for an object type.
+func (s *Summary) GetLinesByType(objType string) scanner.LineCounts {
+	for _, tl := range s.LinesByType {
+		if tl.Type == objType {
+			return tl.Lines
+		}
+	}
+	return scanner.LineCounts{}
+}
@@ -0,0 +1,91 @@
+package counter
+
+import (
+	"sort"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// MemberCount represents the number of members of a given kind, e.g. fields.
+type MemberCount struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// TypeMembers aggregates the members of all objects of one type.
+type TypeMembers struct {
+	Type   string        `json:"type"`
+	Total  int           `json:"total"`
+	Counts []MemberCount `json:"counts"`
+}
+
+// CountMembers counts members by kind in scanner.MemberKinds order, leaving
+// out kinds that do not occur.
+func CountMembers(members []scanner.Member) []MemberCount {
+	counts := make(map[string]int)
+	for _, m := range members {
+		counts[m.Kind]++
+	}
+
+	var result []MemberCount
+	for _, kind := range scanner.MemberKinds {
+		if counts[kind] > 0 {
+			result = append(result, MemberCount{Kind: kind, Count: counts[kind]})
+		}
+	}
+	return result
+}
+
+// summarizeMembers fills in the member totals of the summary, overall and
+// per object type.
+func summarizeMembers(summary *Summary) {
+	var all []scanner.Member
+	for objType, objects := range summary.ObjectsByType {
+		var members []scanner.Member
+		for _, obj := range objects {
+			members = append(members, obj.Members...)
+		}
+		if len(members) == 0 {
+			continue
+		}
+		all = append(all, members...)
+		summary.MembersByType = append(summary.MembersByType, TypeMembers{
+			Type:   objType,
+			Total:  len(members),
+			Counts: CountMembers(members),
+		})
+	}
+
+	summary.TotalMembers = len(all)
+	summary.CountsByMemberKind = CountMembers(all)
+
+	// Sort by total (descending), then by type name
+	sort.Slice(summary.MembersByType, func(i, j int) bool {
+		a, b := summary.MembersByType[i], summary.MembersByType[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Type < b.Type
+	})
+}
+
+// GetMemberCount returns the total number of members of the given kind.
+func (s *Summary) GetMemberCount(kind string) int {
+	for _, c := range s.CountsByMemberKind {
+		if c.Kind == kind {
+			return c.Count
+		}
+	}
+	return 0
+}
+
+// GetTypeMembers returns the member totals for an object type, or nil if
+// objects of that type declare no members.
+func (s *Summary) GetTypeMembers(objType string) *TypeMembers {
+	for i := range s.MembersByType {
+		if s.MembersByType[i].Type == objType {
+			return &s.MembersByType[i]
+		}
+	}
+	return nil
+}
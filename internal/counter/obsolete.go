@@ -0,0 +1,130 @@
+package counter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// ObsoleteKindObject marks an ObsoleteEntry for a whole object rather than a member.
+const ObsoleteKindObject = "object"
+
+// ObsoleteEntry is an object or member marked obsolete.
+type ObsoleteEntry struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+	ObjectName string `json:"objectName"`
+	Kind       string `json:"kind"`
+	MemberID   string `json:"memberId,omitempty"`
+	MemberName string `json:"memberName,omitempty"`
+	State      string `json:"state"`
+	Reason     string `json:"reason,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	FilePath   string `json:"filePath"`
+	Line       int    `json:"line"`
+}
+
+// ObsoleteGroup lists the obsolete objects and members sharing an ObsoleteTag.
+type ObsoleteGroup struct {
+	Tag     string          `json:"tag"`
+	Count   int             `json:"count"`
+	Pending int             `json:"pending"`
+	Removed int             `json:"removed"`
+	Entries []ObsoleteEntry `json:"entries"`
+}
+
+// GroupObsolete collects every obsolete object and member and groups them by
+// ObsoleteTag, ordered by version with untagged entries last.
+func GroupObsolete(objects []scanner.BCObject) []ObsoleteGroup {
+	byTag := make(map[string]*ObsoleteGroup)
+	add := func(entry ObsoleteEntry) {
+		group, ok := byTag[entry.Tag]
+		if !ok {
+			group = &ObsoleteGroup{Tag: entry.Tag}
+			byTag[entry.Tag] = group
+		}
+		group.Count++
+		switch {
+		case strings.EqualFold(entry.State, scanner.ObsoleteStatePending):
+			group.Pending++
+		case strings.EqualFold(entry.State, scanner.ObsoleteStateRemoved):
+			group.Removed++
+		}
+		group.Entries = append(group.Entries, entry)
+	}
+
+	for _, obj := range objects {
+		if obj.Obsolete != nil {
+			add(ObsoleteEntry{
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+				Kind:       ObsoleteKindObject,
+				State:      obj.Obsolete.State,
+				Reason:     obj.Obsolete.Reason,
+				Tag:        obj.Obsolete.Tag,
+				FilePath:   obj.FilePath,
+				Line:       obj.Line,
+			})
+		}
+		for _, m := range obj.ObsoleteMembers {
+			add(ObsoleteEntry{
+				ObjectType: obj.Type,
+				ObjectID:   obj.ID,
+				ObjectName: obj.Name,
+				Kind:       m.Kind,
+				MemberID:   m.ID,
+				MemberName: m.Name,
+				State:      m.Obsolete.State,
+				Reason:     m.Obsolete.Reason,
+				Tag:        m.Obsolete.Tag,
+				FilePath:   obj.FilePath,
+				Line:       m.Line,
+			})
+		}
+	}
+
+	groups := make([]ObsoleteGroup, 0, len(byTag))
+	for _, group := range byTag {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return tagLess(groups[i].Tag, groups[j].Tag)
+	})
+	return groups
+}
+
+// CountObsolete returns the total number of obsolete objects and members.
+func (s *Summary) CountObsolete() int {
+	total := 0
+	for _, g := range s.Obsolete {
+		total += g.Count
+	}
+	return total
+}
+
+// tagLess orders ObsoleteTags such as "9.0" < "24.0" < "24.1" numerically
+// where possible, with the empty tag last.
+func tagLess(a, b string) bool {
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		if errA != nil || errB != nil {
+			if pa[i] != pb[i] {
+				return pa[i] < pb[i]
+			}
+			continue
+		}
+		if na != nb {
+			return na < nb
+		}
+	}
+	return len(pa) < len(pb)
+}
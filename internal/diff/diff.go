@@ -0,0 +1,308 @@
+// Package diff compares two snapshots of a code base, e.g. two releases, and
+// reports the objects added, removed, renamed, renumbered or moved.
+package diff
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// Kind is the kind of a change between two snapshots.
+type Kind string
+
+const (
+	// KindAdded means the object only exists in the new snapshot.
+	KindAdded Kind = "added"
+	// KindRemoved means the object only exists in the old snapshot.
+	KindRemoved Kind = "removed"
+	// KindRenamed means an object of the same type and ID has a new name.
+	KindRenamed Kind = "renamed"
+	// KindRenumbered means an object of the same type and name has a new ID.
+	KindRenumbered Kind = "renumbered"
+	// KindMoved means the object is unchanged but declared in another file.
+	KindMoved Kind = "moved"
+)
+
+// Kinds lists the change kinds in report order.
+var Kinds = []Kind{KindAdded, KindRemoved, KindRenamed, KindRenumbered, KindMoved}
+
+// Snapshot is one side of a comparison. File paths of the objects are
+// compared relative to Root, so that two checkouts in different directories
+// can be compared.
+type Snapshot struct {
+	// Name identifies the snapshot in reports, e.g. its path.
+	Name    string
+	Root    string
+	Objects []scanner.BCObject
+}
+
+// Change is one object added, removed, renamed, renumbered or moved. Old is
+// nil for added objects, New for removed ones.
+type Change struct {
+	Kind Kind              `json:"kind"`
+	Type string            `json:"type"`
+	Old  *scanner.BCObject `json:"old,omitempty"`
+	New  *scanner.BCObject `json:"new,omitempty"`
+	// OldPath and NewPath are the file paths relative to the snapshot roots.
+	OldPath string `json:"oldPath,omitempty"`
+	NewPath string `json:"newPath,omitempty"`
+}
+
+// KindCount is the number of changes of a kind.
+type KindCount struct {
+	Kind  Kind `json:"kind"`
+	Count int  `json:"count"`
+}
+
+// TypeDelta is the change in the number of objects of a type.
+type TypeDelta struct {
+	Type  string `json:"type"`
+	Old   int    `json:"old"`
+	New   int    `json:"new"`
+	Delta int    `json:"delta"`
+}
+
+// Report is the result of comparing two snapshots.
+type Report struct {
+	Old          string      `json:"old"`
+	New          string      `json:"new"`
+	OldTotal     int         `json:"oldTotal"`
+	NewTotal     int         `json:"newTotal"`
+	TotalChanges int         `json:"totalChanges"`
+	CountsByKind []KindCount `json:"countsByKind"`
+	TypeDeltas   []TypeDelta `json:"typeDeltas"`
+	Changes      []Change    `json:"changes"`
+}
+
+// entry is an object with its file path relative to the snapshot root.
+type entry struct {
+	obj     scanner.BCObject
+	relPath string
+	matched bool
+}
+
+// Compare matches the objects of snapshot from to those of snapshot to,
+// within the same app and object type: first by ID and name (unchanged, or
+// moved when the file differs), then by ID (renamed), then by namespace and
+// name (renumbered). Names compare case-insensitively. A renamed or
+// renumbered object that also changed files is reported as moved as well.
+// Objects left over are removed or added. Changes are sorted by kind, type
+// and numeric ID.
+func Compare(from, to Snapshot) *Report {
+	oldEntries := entriesOf(from)
+	newEntries := entriesOf(to)
+
+	report := &Report{
+		Old:      from.Name,
+		New:      to.Name,
+		OldTotal: len(from.Objects),
+		NewTotal: len(to.Objects),
+	}
+
+	// match pairs unmatched objects with the same key, in declaration order
+	match := func(key func(scanner.BCObject) (string, bool), pair func(o, n *entry)) {
+		byKey := make(map[string][]*entry)
+		for _, e := range oldEntries {
+			if k, ok := key(e.obj); ok && !e.matched {
+				byKey[k] = append(byKey[k], e)
+			}
+		}
+		for _, n := range newEntries {
+			k, ok := key(n.obj)
+			if !ok || n.matched || len(byKey[k]) == 0 {
+				continue
+			}
+			o := byKey[k][0]
+			byKey[k] = byKey[k][1:]
+			o.matched, n.matched = true, true
+			pair(o, n)
+		}
+	}
+
+	// addMoved records a move for a pair matched in any pass
+	addMoved := func(o, n *entry) {
+		if o.relPath != n.relPath {
+			report.addChange(KindMoved, o, n)
+		}
+	}
+
+	match(func(obj scanner.BCObject) (string, bool) {
+		return objectKey(obj) + "|" + obj.ID + "|" + nameKey(obj), true
+	}, addMoved)
+	match(func(obj scanner.BCObject) (string, bool) {
+		return objectKey(obj) + "|" + obj.ID, obj.ID != ""
+	}, func(o, n *entry) {
+		report.addChange(KindRenamed, o, n)
+		addMoved(o, n)
+	})
+	match(func(obj scanner.BCObject) (string, bool) {
+		return objectKey(obj) + "|" + nameKey(obj), obj.Name != ""
+	}, func(o, n *entry) {
+		report.addChange(KindRenumbered, o, n)
+		addMoved(o, n)
+	})
+
+	for _, o := range oldEntries {
+		if !o.matched {
+			report.addChange(KindRemoved, o, nil)
+		}
+	}
+	for _, n := range newEntries {
+		if !n.matched {
+			report.addChange(KindAdded, nil, n)
+		}
+	}
+
+	kindOrder := make(map[Kind]int)
+	for i, k := range Kinds {
+		kindOrder[k] = i
+	}
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		a, b := report.Changes[i], report.Changes[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return scanner.NumericID(a.ID()) < scanner.NumericID(b.ID())
+	})
+
+	counts := make(map[Kind]int)
+	for _, c := range report.Changes {
+		counts[c.Kind]++
+	}
+	for _, k := range Kinds {
+		report.CountsByKind = append(report.CountsByKind, KindCount{Kind: k, Count: counts[k]})
+	}
+	report.TotalChanges = len(report.Changes)
+	report.TypeDeltas = typeDeltas(from.Objects, to.Objects)
+	return report
+}
+
+// GetCountByKind returns the number of changes of the given kind.
+func (r *Report) GetCountByKind(kind Kind) int {
+	for _, c := range r.CountsByKind {
+		if c.Kind == kind {
+			return c.Count
+		}
+	}
+	return 0
+}
+
+// ID returns the object ID the change is listed under: the new ID, or the
+// old one for removed objects.
+func (c Change) ID() string {
+	if c.New != nil {
+		return c.New.ID
+	}
+	return c.Old.ID
+}
+
+// addChange records a change between the entries o and n, either of which may
+// be nil.
+func (r *Report) addChange(kind Kind, o, n *entry) {
+	c := Change{Kind: kind}
+	if o != nil {
+		obj := o.obj
+		c.Type, c.Old, c.OldPath = obj.Type, &obj, o.relPath
+	}
+	if n != nil {
+		obj := n.obj
+		c.Type, c.New, c.NewPath = obj.Type, &obj, n.relPath
+	}
+	r.Changes = append(r.Changes, c)
+}
+
+// typeDeltas counts the objects per type in both snapshots, sorted by type.
+func typeDeltas(from, to []scanner.BCObject) []TypeDelta {
+	deltas := make(map[string]*TypeDelta)
+	get := func(objType string) *TypeDelta {
+		if d, ok := deltas[objType]; ok {
+			return d
+		}
+		d := &TypeDelta{Type: objType}
+		deltas[objType] = d
+		return d
+	}
+	for _, obj := range from {
+		get(obj.Type).Old++
+	}
+	for _, obj := range to {
+		get(obj.Type).New++
+	}
+
+	result := make([]TypeDelta, 0, len(deltas))
+	for _, d := range deltas {
+		d.Delta = d.New - d.Old
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+	return result
+}
+
+func entriesOf(s Snapshot) []*entry {
+	entries := make([]*entry, len(s.Objects))
+	for i, obj := range s.Objects {
+		entries[i] = &entry{obj: obj, relPath: RelPath(s.Root, obj.FilePath)}
+	}
+	return entries
+}
+
+// objectKey identifies the app and type of an object. Apps are identified by
+// their ID, or their name if they have no ID, as versions differ between
+// snapshots.
+func objectKey(obj scanner.BCObject) string {
+	app := ""
+	if obj.App != nil {
+		app = obj.App.ID
+		if app == "" {
+			app = obj.App.Name
+		}
+	}
+	return strings.ToLower(app) + "|" + obj.Type
+}
+
+func nameKey(obj scanner.BCObject) string {
+	return strings.ToLower(obj.Namespace) + "|" + strings.ToLower(obj.Name)
+}
+
+// RelPath returns filePath relative to root with forward slashes, or
+// filePath itself if it is not inside root. Both Windows and Unix separators
+// are accepted, so that snapshots taken on either can be compared.
+func RelPath(root, filePath string) string {
+	p := strings.ReplaceAll(filePath, `\`, "/")
+	r := strings.TrimSuffix(strings.ReplaceAll(root, `\`, "/"), "/")
+	if r == "" {
+		return p
+	}
+	if strings.HasPrefix(p, r+"/") {
+		return p[len(r)+1:]
+	}
+	return p
+}
+
+// CommonRoot returns the deepest directory containing the files of all
+// objects, for snapshots that do not record the directory they were scanned
+// from.
+func CommonRoot(objects []scanner.BCObject) string {
+	var root []string
+	for i, obj := range objects {
+		dir := strings.Split(path.Dir(strings.ReplaceAll(obj.FilePath, `\`, "/")), "/")
+		if i == 0 {
+			root = dir
+			continue
+		}
+		n := 0
+		for n < len(root) && n < len(dir) && root[n] == dir[n] {
+			n++
+		}
+		root = root[:n]
+	}
+	return strings.Join(root, "/")
+}
@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+func TestCompare(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales", Version: "1.0.0.0"}
+	salesNext := &scanner.AppInfo{ID: "1", Name: "Sales", Version: "2.0.0.0"}
+	old := Snapshot{Name: "v1", Root: "/builds/v1", Objects: []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "/builds/v1/src/Post.al", App: sales},
+		{Type: "codeunit", ID: "50101", Name: "Release", FilePath: "/builds/v1/src/Release.al", App: sales},
+		{Type: "table", ID: "50100", Name: "Setup", FilePath: "/builds/v1/src/Setup.al", App: sales},
+		{Type: "page", ID: "50100", Name: "Card", FilePath: "/builds/v1/src/Card.al", App: sales},
+		{Type: "codeunit", ID: "50102", Name: "Gone", FilePath: "/builds/v1/src/Gone.al", App: sales},
+	}}
+	newer := Snapshot{Name: "v2", Root: `C:\builds\v2`, Objects: []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: `C:\builds\v2\src\Posting\Post.al`, App: salesNext},
+		{Type: "codeunit", ID: "50101", Name: "Release Document", FilePath: `C:\builds\v2\src\Release.al`, App: salesNext},
+		{Type: "table", ID: "50110", Name: "SETUP", FilePath: `C:\builds\v2\src\Setup.al`, App: salesNext},
+		{Type: "page", ID: "50100", Name: "Card", FilePath: `C:\builds\v2\src\Card.al`, App: salesNext},
+		{Type: "query", ID: "50100", Name: "Sales Lines", FilePath: `C:\builds\v2\src\Lines.al`, App: salesNext},
+	}}
+
+	report := Compare(old, newer)
+
+	expected := []struct {
+		kind Kind
+		typ  string
+		id   string
+	}{
+		{KindAdded, "query", "50100"},
+		{KindRemoved, "codeunit", "50102"},
+		{KindRenamed, "codeunit", "50101"},
+		{KindRenumbered, "table", "50110"},
+		{KindMoved, "codeunit", "50100"},
+	}
+	if len(report.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(expected), len(report.Changes), report.Changes)
+	}
+	for i, exp := range expected {
+		c := report.Changes[i]
+		if c.Kind != exp.kind || c.Type != exp.typ || c.ID() != exp.id {
+			t.Errorf("change %d: expected %s %s %s, got %s %s %s", i, exp.kind, exp.typ, exp.id, c.Kind, c.Type, c.ID())
+		}
+	}
+
+	moved := report.Changes[4]
+	if moved.OldPath != "src/Post.al" || moved.NewPath != "src/Posting/Post.al" {
+		t.Errorf("expected paths relative to the roots, got %s → %s", moved.OldPath, moved.NewPath)
+	}
+	if renumbered := report.Changes[3]; renumbered.Old.ID != "50100" {
+		t.Errorf("expected the old ID 50100, got %s", renumbered.Old.ID)
+	}
+
+	if report.TotalChanges != 5 || report.GetCountByKind(KindRenamed) != 1 {
+		t.Errorf("unexpected counts %d, %+v", report.TotalChanges, report.CountsByKind)
+	}
+
+	deltas := make(map[string]TypeDelta)
+	for _, d := range report.TypeDeltas {
+		deltas[d.Type] = d
+	}
+	if d := deltas["codeunit"]; d.Old != 3 || d.New != 2 || d.Delta != -1 {
+		t.Errorf("unexpected codeunit delta %+v", d)
+	}
+	if d := deltas["query"]; d.Old != 0 || d.New != 1 || d.Delta != 1 {
+		t.Errorf("unexpected query delta %+v", d)
+	}
+}
+
+func TestCompareRenamedAndMoved(t *testing.T) {
+	old := Snapshot{Root: "/v1", Objects: []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "/v1/src/Post.al"},
+		{Type: "table", ID: "50100", Name: "Setup", FilePath: "/v1/src/Setup.al"},
+	}}
+	newer := Snapshot{Root: "/v2", Objects: []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post Document", FilePath: "/v2/src/Posting/PostDocument.al"},
+		{Type: "table", ID: "50110", Name: "Setup", FilePath: "/v2/src/Setup/Setup.al"},
+	}}
+
+	report := Compare(old, newer)
+
+	expected := []struct {
+		kind    Kind
+		typ     string
+		newPath string
+	}{
+		{KindRenamed, "codeunit", "src/Posting/PostDocument.al"},
+		{KindRenumbered, "table", "src/Setup/Setup.al"},
+		{KindMoved, "codeunit", "src/Posting/PostDocument.al"},
+		{KindMoved, "table", "src/Setup/Setup.al"},
+	}
+	if len(report.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(expected), len(report.Changes), report.Changes)
+	}
+	for i, exp := range expected {
+		c := report.Changes[i]
+		if c.Kind != exp.kind || c.Type != exp.typ || c.NewPath != exp.newPath {
+			t.Errorf("change %d: expected %s %s %s, got %s %s %s", i, exp.kind, exp.typ, exp.newPath, c.Kind, c.Type, c.NewPath)
+		}
+	}
+}
+
+func TestCompareAppsAndDuplicates(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales"}
+	service := &scanner.AppInfo{ID: "2", Name: "Service"}
+	old := Snapshot{Objects: []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "a.al", App: sales},
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "b.al", App: sales},
+	}}
+	newer := Snapshot{Objects: []scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "b.al", App: sales},
+		{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "a.al", App: service},
+	}}
+
+	report := Compare(old, newer)
+
+	// The duplicate pairs with the first declaration, a.al with b.al; the
+	// object in the other app is new
+	if report.GetCountByKind(KindMoved) != 1 || report.GetCountByKind(KindRemoved) != 1 || report.GetCountByKind(KindAdded) != 1 {
+		t.Errorf("unexpected changes %+v", report.CountsByKind)
+	}
+	if report.GetCountByKind(KindRenamed) != 0 {
+		t.Error("objects in different apps should not be matched")
+	}
+}
+
+func TestRelPathAndCommonRoot(t *testing.T) {
+	tests := []struct {
+		root, path, expected string
+	}{
+		{"/src/app", "/src/app/Post.al", "Post.al"},
+		{"/src/app/", "/src/app/sub/Post.al", "sub/Post.al"},
+		{`C:\src\app`, `C:\src\app\Post.al`, "Post.al"},
+		{"/src/app", "/src/application/Post.al", "/src/application/Post.al"},
+		{"", "Post.al", "Post.al"},
+	}
+	for _, tt := range tests {
+		if got := RelPath(tt.root, tt.path); got != tt.expected {
+			t.Errorf("RelPath(%q, %q): expected %q, got %q", tt.root, tt.path, tt.expected, got)
+		}
+	}
+
+	objects := []scanner.BCObject{
+		{FilePath: "/repo/app/src/Post.al"},
+		{FilePath: "/repo/app/test/PostTest.al"},
+	}
+	if root := CommonRoot(objects); root != "/repo/app" {
+		t.Errorf("expected /repo/app, got %q", root)
+	}
+	if root := CommonRoot(nil); root != "" {
+		t.Errorf("expected no root, got %q", root)
+	}
+}
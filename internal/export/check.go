@@ -0,0 +1,239 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/check"
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// findingLocation returns "file:line" for a finding, or just the file for
+// findings without a position.
+func findingLocation(f check.Finding) string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// CheckToConsole formats a check report for console output.
+func CheckToConsole(report *check.Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString("═══════════════════════════════════════════\n")
+	sb.WriteString("       Check Findings\n")
+	sb.WriteString("═══════════════════════════════════════════\n\n")
+
+	for _, f := range report.Findings {
+		sb.WriteString(fmt.Sprintf("  %s\n", f))
+	}
+	if len(report.Findings) > 0 {
+		sb.WriteString("\n───────────────────────────────────────────\n")
+	}
+
+	ruleLen := 5
+	for _, c := range report.CountsByRule {
+		if len(c.Rule) > ruleLen {
+			ruleLen = len(c.Rule)
+		}
+	}
+	for _, c := range report.CountsByRule {
+		padding := strings.Repeat(" ", ruleLen-len(c.Rule))
+		sb.WriteString(fmt.Sprintf("  %s%s : %d\n", c.Rule, padding, c.Count))
+	}
+	padding := strings.Repeat(" ", ruleLen-5)
+	sb.WriteString(fmt.Sprintf("  TOTAL%s : %d\n", padding, report.TotalFindings))
+	sb.WriteString("═══════════════════════════════════════════\n")
+
+	return sb.String()
+}
+
+// CheckToJSON exports a check report to a JSON file.
+func CheckToJSON(report *check.Report, filePath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// CheckToExcel exports a check report to an Excel file.
+func CheckToExcel(report *check.Report, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Create Summary sheet
+	summarySheet := "Summary"
+	f.SetSheetName("Sheet1", summarySheet)
+
+	f.SetCellValue(summarySheet, "A1", "Check Findings")
+	f.SetCellValue(summarySheet, "A3", "Rule")
+	f.SetCellValue(summarySheet, "B3", "Count")
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "#FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#4472C4"}, Pattern: 1},
+	})
+	f.SetCellStyle(summarySheet, "A3", "B3", headerStyle)
+
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 14},
+	})
+	f.SetCellStyle(summarySheet, "A1", "A1", titleStyle)
+
+	row := 4
+	for _, c := range report.CountsByRule {
+		f.SetCellValue(summarySheet, fmt.Sprintf("A%d", row), c.Rule)
+		f.SetCellValue(summarySheet, fmt.Sprintf("B%d", row), c.Count)
+		row++
+	}
+
+	row++
+	f.SetCellValue(summarySheet, fmt.Sprintf("A%d", row), "TOTAL")
+	f.SetCellValue(summarySheet, fmt.Sprintf("B%d", row), report.TotalFindings)
+	totalStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+	f.SetCellStyle(summarySheet, fmt.Sprintf("A%d", row), fmt.Sprintf("B%d", row), totalStyle)
+
+	f.SetColWidth(summarySheet, "A", "A", 25)
+	f.SetColWidth(summarySheet, "B", "B", 12)
+
+	// Create Findings sheet
+	findingsSheet := "Findings"
+	f.NewSheet(findingsSheet)
+
+	f.SetCellValue(findingsSheet, "A1", "Rule")
+	f.SetCellValue(findingsSheet, "B1", "File")
+	f.SetCellValue(findingsSheet, "C1", "Line")
+	f.SetCellValue(findingsSheet, "D1", "Column")
+	f.SetCellValue(findingsSheet, "E1", "App")
+	f.SetCellValue(findingsSheet, "F1", "Object Type")
+	f.SetCellValue(findingsSheet, "G1", "Object ID")
+	f.SetCellValue(findingsSheet, "H1", "Object Name")
+	f.SetCellValue(findingsSheet, "I1", "Member Kind")
+	f.SetCellValue(findingsSheet, "J1", "Member ID")
+	f.SetCellValue(findingsSheet, "K1", "Member Name")
+	f.SetCellValue(findingsSheet, "L1", "Message")
+	f.SetCellStyle(findingsSheet, "A1", "L1", headerStyle)
+
+	row = 2
+	for _, finding := range report.Findings {
+		f.SetCellValue(findingsSheet, fmt.Sprintf("A%d", row), finding.Rule)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("B%d", row), finding.File)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("C%d", row), finding.Line)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("D%d", row), finding.Column)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("E%d", row), finding.App)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("F%d", row), finding.ObjectType)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("G%d", row), finding.ObjectID)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("H%d", row), finding.ObjectName)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("I%d", row), finding.MemberKind)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("J%d", row), finding.MemberID)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("K%d", row), finding.MemberName)
+		f.SetCellValue(findingsSheet, fmt.Sprintf("L%d", row), finding.Message)
+		row++
+	}
+
+	f.SetColWidth(findingsSheet, "A", "A", 15)
+	f.SetColWidth(findingsSheet, "B", "B", 60)
+	f.SetColWidth(findingsSheet, "C", "D", 10)
+	f.SetColWidth(findingsSheet, "E", "E", 30)
+	f.SetColWidth(findingsSheet, "F", "G", 15)
+	f.SetColWidth(findingsSheet, "H", "H", 40)
+	f.SetColWidth(findingsSheet, "I", "J", 12)
+	f.SetColWidth(findingsSheet, "K", "K", 30)
+	f.SetColWidth(findingsSheet, "L", "L", 80)
+
+	return f.SaveAs(filePath)
+}
+
+// CheckToPDF exports a check report to a PDF file.
+func CheckToPDF(report *check.Report, filePath string) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Check Findings", false)
+	pdf.SetAuthor("BC Objects Counter", false)
+
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 18)
+	pdf.Cell(0, 12, "Check Findings")
+	pdf.Ln(16)
+
+	// Rule table header
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetFillColor(68, 114, 196)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.CellFormat(80, 8, "Rule", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(40, 8, "Count", "1", 1, "C", true, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetTextColor(0, 0, 0)
+	for i, c := range report.CountsByRule {
+		fill := i%2 == 0
+		if fill {
+			pdf.SetFillColor(240, 240, 240)
+		}
+		pdf.CellFormat(80, 7, c.Rule, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%d", c.Count), "1", 1, "C", fill, 0, "")
+	}
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(200, 200, 200)
+	pdf.CellFormat(80, 8, "TOTAL", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(40, 8, fmt.Sprintf("%d", report.TotalFindings), "1", 1, "C", true, 0, "")
+
+	if len(report.Findings) == 0 {
+		return pdf.OutputFileAndClose(filePath)
+	}
+
+	// Findings section (new page)
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 10, "Findings")
+	pdf.Ln(14)
+
+	writeHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(25, 7, "Rule", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(65, 7, "Location", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(100, 7, "Message", "1", 1, "L", true, 0, "")
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	writeHeader()
+
+	for i, finding := range report.Findings {
+		if pdf.GetY() > 270 {
+			pdf.AddPage()
+			writeHeader()
+		}
+
+		fill := i%2 == 0
+		if fill {
+			pdf.SetFillColor(245, 245, 245)
+		}
+
+		location := findingLocation(finding)
+		if len(location) > 38 {
+			location = "..." + location[len(location)-35:]
+		}
+		message := finding.Message
+		if len(message) > 62 {
+			message = message[:59] + "..."
+		}
+
+		pdf.CellFormat(25, 6, finding.Rule, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(65, 6, location, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(100, 6, message, "1", 1, "L", fill, 0, "")
+	}
+
+	return pdf.OutputFileAndClose(filePath)
+}
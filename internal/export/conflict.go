@@ -0,0 +1,273 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/conflict"
+	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// collisionLabel describes a collision, e.g.
+// "codeunit ID 50101 in Sales (Contoso) 1.0.0.0".
+func collisionLabel(c conflict.Collision) string {
+	value := c.Value
+	if c.Kind == counter.DuplicateName {
+		value = `"` + c.Value + `"`
+	}
+	label := fmt.Sprintf("%s %s %s", c.Type, duplicateKindLabel(c.Kind), value)
+	if c.App != (scanner.AppInfo{}) {
+		label += " in " + c.App.Label()
+	}
+	return label
+}
+
+// sideLabel describes a compared revision, e.g. "feature (1a2b3c4)".
+func sideLabel(side conflict.Side) string {
+	commit := side.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("%s (%s)", side.Ref, commit)
+}
+
+// collisionRows flattens a collision into one row per object: side, object.
+func collisionRows(c conflict.Collision) ([]string, []scanner.BCObject) {
+	var sides []string
+	var objects []scanner.BCObject
+	for _, obj := range c.Ours {
+		sides = append(sides, "ours")
+		objects = append(objects, obj)
+	}
+	for _, obj := range c.Theirs {
+		sides = append(sides, "theirs")
+		objects = append(objects, obj)
+	}
+	return sides, objects
+}
+
+// ConflictsToConsole formats a branch conflict report for console output.
+func ConflictsToConsole(report *conflict.Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString("═══════════════════════════════════════════\n")
+	sb.WriteString("       Branch Conflicts\n")
+	sb.WriteString("═══════════════════════════════════════════\n\n")
+
+	mergeBase := "(none)"
+	if len(report.MergeBase) > 7 {
+		mergeBase = report.MergeBase[:7]
+	}
+	sb.WriteString(fmt.Sprintf("  Repository : %s\n", report.Repository))
+	sb.WriteString(fmt.Sprintf("  Ours       : %s, %d object(s) added\n", sideLabel(report.Ours), report.Ours.Added))
+	sb.WriteString(fmt.Sprintf("  Theirs     : %s, %d object(s) added\n", sideLabel(report.Theirs), report.Theirs.Added))
+	sb.WriteString(fmt.Sprintf("  Merge base : %s\n", mergeBase))
+
+	if len(report.Collisions) > 0 {
+		sb.WriteString("\n───────────────────────────────────────────\n")
+	}
+	for _, c := range report.Collisions {
+		sb.WriteString(fmt.Sprintf("  %s\n", collisionLabel(c)))
+		sides, objects := collisionRows(c)
+		for i, obj := range objects {
+			sb.WriteString(fmt.Sprintf("    %-6s  %s:%d  %s %s %s\n", sides[i], obj.FilePath, obj.Line, obj.Type, obj.ID, obj.Name))
+		}
+	}
+
+	sb.WriteString("───────────────────────────────────────────\n")
+	sb.WriteString(fmt.Sprintf("  TOTAL : %d\n", report.TotalCollisions))
+	sb.WriteString("═══════════════════════════════════════════\n")
+
+	return sb.String()
+}
+
+// ConflictsToJSON exports a branch conflict report to a JSON file.
+func ConflictsToJSON(report *conflict.Report, filePath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// ConflictsToExcel exports a branch conflict report to an Excel file.
+func ConflictsToExcel(report *conflict.Report, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Create Summary sheet
+	summarySheet := "Summary"
+	f.SetSheetName("Sheet1", summarySheet)
+
+	f.SetCellValue(summarySheet, "A1", "Branch Conflicts")
+	f.SetCellValue(summarySheet, "A3", "Repository")
+	f.SetCellValue(summarySheet, "B3", report.Repository)
+	f.SetCellValue(summarySheet, "A4", "Ours")
+	f.SetCellValue(summarySheet, "B4", report.Ours.Ref)
+	f.SetCellValue(summarySheet, "C4", report.Ours.Commit)
+	f.SetCellValue(summarySheet, "D4", report.Ours.Added)
+	f.SetCellValue(summarySheet, "A5", "Theirs")
+	f.SetCellValue(summarySheet, "B5", report.Theirs.Ref)
+	f.SetCellValue(summarySheet, "C5", report.Theirs.Commit)
+	f.SetCellValue(summarySheet, "D5", report.Theirs.Added)
+	f.SetCellValue(summarySheet, "A6", "Merge Base")
+	f.SetCellValue(summarySheet, "C6", report.MergeBase)
+	f.SetCellValue(summarySheet, "A8", "TOTAL")
+	f.SetCellValue(summarySheet, "B8", report.TotalCollisions)
+
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 14},
+	})
+	f.SetCellStyle(summarySheet, "A1", "A1", titleStyle)
+	totalStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+	f.SetCellStyle(summarySheet, "A3", "A6", totalStyle)
+	f.SetCellStyle(summarySheet, "A8", "B8", totalStyle)
+
+	f.SetColWidth(summarySheet, "A", "A", 15)
+	f.SetColWidth(summarySheet, "B", "B", 40)
+	f.SetColWidth(summarySheet, "C", "C", 45)
+	f.SetColWidth(summarySheet, "D", "D", 10)
+
+	// Create Collisions sheet with one row per object
+	collisionsSheet := "Collisions"
+	f.NewSheet(collisionsSheet)
+
+	f.SetCellValue(collisionsSheet, "A1", "Collision")
+	f.SetCellValue(collisionsSheet, "B1", "Type")
+	f.SetCellValue(collisionsSheet, "C1", "Value")
+	f.SetCellValue(collisionsSheet, "D1", "App")
+	f.SetCellValue(collisionsSheet, "E1", "Side")
+	f.SetCellValue(collisionsSheet, "F1", "Object ID")
+	f.SetCellValue(collisionsSheet, "G1", "Object Name")
+	f.SetCellValue(collisionsSheet, "H1", "File Path")
+	f.SetCellValue(collisionsSheet, "I1", "Line")
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "#FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#4472C4"}, Pattern: 1},
+	})
+	f.SetCellStyle(collisionsSheet, "A1", "I1", headerStyle)
+
+	row := 2
+	for _, c := range report.Collisions {
+		sides, objects := collisionRows(c)
+		for i, obj := range objects {
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("A%d", row), duplicateKindLabel(c.Kind))
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("B%d", row), c.Type)
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("C%d", row), c.Value)
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("D%d", row), c.App.Label())
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("E%d", row), sides[i])
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("F%d", row), obj.ID)
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("G%d", row), obj.Name)
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("H%d", row), obj.FilePath)
+			f.SetCellValue(collisionsSheet, fmt.Sprintf("I%d", row), obj.Line)
+			row++
+		}
+	}
+
+	f.SetColWidth(collisionsSheet, "A", "B", 15)
+	f.SetColWidth(collisionsSheet, "C", "C", 30)
+	f.SetColWidth(collisionsSheet, "D", "D", 30)
+	f.SetColWidth(collisionsSheet, "E", "F", 10)
+	f.SetColWidth(collisionsSheet, "G", "G", 40)
+	f.SetColWidth(collisionsSheet, "H", "H", 60)
+	f.SetColWidth(collisionsSheet, "I", "I", 8)
+
+	return f.SaveAs(filePath)
+}
+
+// ConflictsToPDF exports a branch conflict report to a PDF file.
+func ConflictsToPDF(report *conflict.Report, filePath string) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Branch Conflicts", false)
+	pdf.SetAuthor("BC Objects Counter", false)
+
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 18)
+	pdf.Cell(0, 12, "Branch Conflicts")
+	pdf.Ln(16)
+
+	mergeBase := "(none)"
+	if len(report.MergeBase) > 7 {
+		mergeBase = report.MergeBase[:7]
+	}
+	pdf.SetFont("Arial", "", 10)
+	for _, line := range [][2]string{
+		{"Ours", fmt.Sprintf("%s, %d object(s) added", sideLabel(report.Ours), report.Ours.Added)},
+		{"Theirs", fmt.Sprintf("%s, %d object(s) added", sideLabel(report.Theirs), report.Theirs.Added)},
+		{"Merge base", mergeBase},
+		{"Collisions", fmt.Sprintf("%d", report.TotalCollisions)},
+	} {
+		pdf.CellFormat(30, 7, line[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, line[1], "", 1, "L", false, 0, "")
+	}
+
+	if len(report.Collisions) == 0 {
+		return pdf.OutputFileAndClose(filePath)
+	}
+	pdf.Ln(6)
+
+	writeHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(55, 7, "Collision", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(15, 7, "Side", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(50, 7, "Object", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(70, 7, "Location", "1", 1, "L", true, 0, "")
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	writeHeader()
+
+	for i, c := range report.Collisions {
+		sides, objects := collisionRows(c)
+		label := fmt.Sprintf("%s %s %s", c.Type, duplicateKindLabel(c.Kind), c.Value)
+		if len(label) > 33 {
+			label = label[:30] + "..."
+		}
+
+		fill := i%2 == 0
+		if fill {
+			pdf.SetFillColor(245, 245, 245)
+		}
+		for j, obj := range objects {
+			if pdf.GetY() > 270 {
+				pdf.AddPage()
+				writeHeader()
+				if fill {
+					pdf.SetFillColor(245, 245, 245)
+				}
+			}
+
+			object := strings.TrimSpace(fmt.Sprintf("%s %s", obj.ID, obj.Name))
+			if len(object) > 30 {
+				object = object[:27] + "..."
+			}
+			location := fmt.Sprintf("%s:%d", obj.FilePath, obj.Line)
+			if len(location) > 42 {
+				location = "..." + location[len(location)-39:]
+			}
+			if j > 0 {
+				label = ""
+			}
+
+			pdf.CellFormat(55, 6, label, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(15, 6, sides[j], "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(50, 6, object, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(70, 6, location, "1", 1, "L", fill, 0, "")
+		}
+	}
+
+	return pdf.OutputFileAndClose(filePath)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
 )
 
 // ToConsole formats the summary for console output.
@@ -35,5 +36,235 @@ func ToConsole(summary *counter.Summary) string {
 	sb.WriteString(fmt.Sprintf("  TOTAL%s : %d\n", padding, summary.TotalObjects))
 	sb.WriteString("═══════════════════════════════════════════\n")
 
+	// One section per app, only shown when objects belong to apps
+	for _, app := range summary.Apps {
+		sb.WriteString(fmt.Sprintf("\n  App: %s\n", app.App.Label()))
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, c := range app.CountsByType {
+			padding := strings.Repeat(" ", maxLen-len(c.Type))
+			sb.WriteString(fmt.Sprintf("  %s%s : %d\n", c.Type, padding, c.Count))
+		}
+		padding := strings.Repeat(" ", maxLen-5)
+		sb.WriteString(fmt.Sprintf("  TOTAL%s : %d\n", padding, app.TotalObjects))
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Namespace breakdown, only shown for code that uses namespaces
+	if summary.HasNamespaces() {
+		sb.WriteString("\n  By Namespace\n")
+		sb.WriteString("───────────────────────────────────────────\n")
+
+		nsLen := 0
+		for _, c := range summary.CountsByNamespace {
+			if l := len(namespaceLabel(c.Namespace)); l > nsLen {
+				nsLen = l
+			}
+		}
+		for _, c := range summary.CountsByNamespace {
+			label := namespaceLabel(c.Namespace)
+			padding := strings.Repeat(" ", nsLen-len(label))
+			sb.WriteString(fmt.Sprintf("  %s%s : %d\n", label, padding, c.Count))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Member totals, e.g. how many fields and procedures the objects declare
+	if summary.TotalMembers > 0 {
+		sb.WriteString("\n  Members\n")
+		sb.WriteString("───────────────────────────────────────────\n")
+
+		kindLen := 5
+		for _, c := range summary.CountsByMemberKind {
+			if len(c.Kind) > kindLen {
+				kindLen = len(c.Kind)
+			}
+		}
+		for _, c := range summary.CountsByMemberKind {
+			padding := strings.Repeat(" ", kindLen-len(c.Kind))
+			sb.WriteString(fmt.Sprintf("  %s%s : %d\n", c.Kind, padding, c.Count))
+		}
+		padding := strings.Repeat(" ", kindLen-5)
+		sb.WriteString(fmt.Sprintf("  TOTAL%s : %d\n", padding, summary.TotalMembers))
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Duplicate IDs and names, which the compiler would reject
+	if len(summary.Duplicates) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  Duplicates (%d)\n", len(summary.Duplicates)))
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, d := range summary.Duplicates {
+			sb.WriteString(fmt.Sprintf("  %s\n", duplicateLabel(d)))
+			for _, obj := range d.Objects {
+				sb.WriteString(fmt.Sprintf("    %s:%d  %s %s %s\n", obj.FilePath, obj.Line, obj.Type, obj.ID, obj.Name))
+			}
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Line counts, with the code lines of each object type
+	if summary.Lines.Physical > 0 {
+		sb.WriteString("\n  Lines of Code\n")
+		sb.WriteString("───────────────────────────────────────────\n")
+		sb.WriteString(fmt.Sprintf("  physical : %d\n", summary.Lines.Physical))
+		sb.WriteString(fmt.Sprintf("  code     : %d\n", summary.Lines.Code))
+		sb.WriteString(fmt.Sprintf("  comment  : %d\n", summary.Lines.Comment))
+		sb.WriteString(fmt.Sprintf("  blank    : %d\n", summary.Lines.Blank))
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, tl := range summary.LinesByType {
+			padding := strings.Repeat(" ", maxLen-len(tl.Type))
+			sb.WriteString(fmt.Sprintf("  %s%s : %d code lines\n", tl.Type, padding, tl.Lines.Code))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// The most complex procedures and triggers, as candidates for review
+	if len(summary.MostComplex) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  Most Complex (top %d)\n", len(summary.MostComplex)))
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, r := range summary.MostComplex {
+			sb.WriteString(fmt.Sprintf("  %3d  %s (nesting %d)\n", r.Complexity, routineLabel(r), r.Nesting))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Obsolete objects and members, grouped by the release they are tagged for
+	if len(summary.Obsolete) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  Obsolete by Tag (%d)\n", summary.CountObsolete()))
+		sb.WriteString("───────────────────────────────────────────\n")
+
+		tagLen := 0
+		for _, g := range summary.Obsolete {
+			if l := len(obsoleteTagLabel(g.Tag)); l > tagLen {
+				tagLen = l
+			}
+		}
+		for _, g := range summary.Obsolete {
+			label := obsoleteTagLabel(g.Tag)
+			padding := strings.Repeat(" ", tagLen-len(label))
+			sb.WriteString(fmt.Sprintf("  %s%s : %d (%d pending, %d removed)\n", label, padding, g.Count, g.Pending, g.Removed))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Property breakdown, only shown with --group-by
+	if summary.ByProperty != nil {
+		sb.WriteString(fmt.Sprintf("\n  By %s\n", summary.ByProperty.Property))
+		sb.WriteString("───────────────────────────────────────────\n")
+
+		valueLen := 0
+		for _, c := range summary.ByProperty.Counts {
+			if l := len(propertyLabel(c.Value)); l > valueLen {
+				valueLen = l
+			}
+		}
+		for _, c := range summary.ByProperty.Counts {
+			label := propertyLabel(c.Value)
+			padding := strings.Repeat(" ", valueLen-len(label))
+			sb.WriteString(fmt.Sprintf("  %s%s : %d\n", label, padding, c.Count))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Objects excluded by the preprocessor, only present with --show-inactive
+	if len(summary.InactiveObjects) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  Inactive (not counted): %d\n", len(summary.InactiveObjects)))
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, obj := range summary.InactiveObjects {
+			sb.WriteString(fmt.Sprintf("  %-15s %-8s %s\n", obj.Type, obj.ID, obj.Name))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	// Diagnostics are listed in full with --verbose; the summary only counts them
+	if len(summary.Diagnostics) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  Diagnostics: %d error(s), %d warning(s)\n",
+			summary.CountDiagnostics(scanner.SeverityError),
+			summary.CountDiagnostics(scanner.SeverityWarning)))
+	}
+
+	return sb.String()
+}
+
+// FormatDiagnostics lists every diagnostic on its own line.
+func FormatDiagnostics(diagnostics []scanner.Diagnostic) string {
+	var sb strings.Builder
+	for _, d := range diagnostics {
+		sb.WriteString(d.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// namespaceLabel returns the display name for a namespace, using a
+// placeholder for objects declared outside any namespace.
+func namespaceLabel(ns string) string {
+	if ns == "" {
+		return "(no namespace)"
+	}
+	return ns
+}
+
+// propertyLabel returns the display value for a property breakdown, using a
+// placeholder for objects that do not set the property.
+func propertyLabel(value string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return value
+}
+
+// obsoleteTagLabel returns the display name for an ObsoleteTag, using a
+// placeholder for obsolete items without a tag.
+func obsoleteTagLabel(tag string) string {
+	if tag == "" {
+		return "(no tag)"
+	}
+	return tag
+}
+
+// obsoleteItemLabel describes what an obsolete entry refers to, e.g.
+// "field 2 Legacy Code" or "object".
+func obsoleteItemLabel(entry counter.ObsoleteEntry) string {
+	if entry.Kind == counter.ObsoleteKindObject {
+		return entry.Kind
+	}
+	return strings.Join(strings.Fields(fmt.Sprintf("%s %s %s", entry.Kind, entry.MemberID, entry.MemberName)), " ")
+}
+
+// duplicateLabel describes a duplicate, e.g. `codeunit ID 50123 (2x)` or
+// `table name "Sales Log" (2x) in Sales (Contoso) 1.0.0.0`.
+func duplicateLabel(d counter.Duplicate) string {
+	value := d.Value
+	if d.Kind == counter.DuplicateName {
+		value = `"` + d.Value + `"`
+	}
+	label := fmt.Sprintf("%s %s %s (%dx)", d.Type, duplicateKindLabel(d.Kind), value, len(d.Objects))
+	if d.App != (scanner.AppInfo{}) {
+		label += " in " + d.App.Label()
+	}
+	return label
+}
+
+// duplicateKindLabel returns "ID" or "name" for a duplicate kind.
+func duplicateKindLabel(kind string) string {
+	if kind == counter.DuplicateID {
+		return "ID"
+	}
+	return kind
+}
+
+// routineLabel identifies a procedure or trigger by its object, e.g.
+// "codeunit 50100 Sales-Post :: Post".
+func routineLabel(r counter.RoutineComplexity) string {
+	object := strings.Join(strings.Fields(fmt.Sprintf("%s %s %s", r.ObjectType, r.ObjectID, r.ObjectName)), " ")
+	return fmt.Sprintf("%s :: %s", object, r.Name)
+}
+
+// FormatComplexityViolations lists the routines exceeding a complexity limit.
+func FormatComplexityViolations(routines []counter.RoutineComplexity, limit int) string {
+	var sb strings.Builder
+	for _, r := range routines {
+		sb.WriteString(fmt.Sprintf("%s:%d: complexity %d exceeds %d: %s\n", r.FilePath, r.Line, r.Complexity, limit, routineLabel(r)))
+	}
 	return sb.String()
 }
@@ -0,0 +1,282 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/diff"
+	"github.com/xuri/excelize/v2"
+)
+
+// kindTitles are the section titles of the change kinds.
+var kindTitles = map[diff.Kind]string{
+	diff.KindAdded:      "Added",
+	diff.KindRemoved:    "Removed",
+	diff.KindRenamed:    "Renamed",
+	diff.KindRenumbered: "Renumbered",
+	diff.KindMoved:      "Moved",
+}
+
+// changeDescription describes a change, e.g. `codeunit 50100 "Post" → "Post
+// Sales"` for a rename or `codeunit 50100 "Post"  src/Post.al` for an added
+// object.
+func changeDescription(c diff.Change) string {
+	switch c.Kind {
+	case diff.KindAdded:
+		return fmt.Sprintf("%s %s %q  %s", c.Type, c.New.ID, c.New.Name, c.NewPath)
+	case diff.KindRemoved:
+		return fmt.Sprintf("%s %s %q  %s", c.Type, c.Old.ID, c.Old.Name, c.OldPath)
+	case diff.KindRenamed:
+		return fmt.Sprintf("%s %s %q → %q", c.Type, c.New.ID, c.Old.Name, c.New.Name)
+	case diff.KindRenumbered:
+		return fmt.Sprintf("%s %q %s → %s", c.Type, c.New.Name, c.Old.ID, c.New.ID)
+	default:
+		return fmt.Sprintf("%s %s %q  %s → %s", c.Type, c.New.ID, c.New.Name, c.OldPath, c.NewPath)
+	}
+}
+
+// signed formats a delta with an explicit sign, e.g. "+2".
+func signed(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// DiffToConsole formats a snapshot diff for console output.
+func DiffToConsole(report *diff.Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString("═══════════════════════════════════════════\n")
+	sb.WriteString("       Snapshot Diff\n")
+	sb.WriteString("═══════════════════════════════════════════\n\n")
+
+	sb.WriteString(fmt.Sprintf("  Old : %s (%d objects)\n", report.Old, report.OldTotal))
+	sb.WriteString(fmt.Sprintf("  New : %s (%d objects)\n\n", report.New, report.NewTotal))
+
+	for _, c := range report.CountsByKind {
+		padding := strings.Repeat(" ", 10-len(c.Kind))
+		sb.WriteString(fmt.Sprintf("  %s%s : %d\n", c.Kind, padding, c.Count))
+	}
+
+	sb.WriteString("\n───────────────────────────────────────────\n")
+	sb.WriteString(fmt.Sprintf("  TOTAL      : %d\n", report.TotalChanges))
+	sb.WriteString("═══════════════════════════════════════════\n")
+
+	// Per-type deltas, for the types whose count changed
+	var changed []diff.TypeDelta
+	for _, d := range report.TypeDeltas {
+		if d.Delta != 0 {
+			changed = append(changed, d)
+		}
+	}
+	if len(changed) > 0 {
+		sb.WriteString("\n  Objects by Type\n")
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, d := range changed {
+			padding := ""
+			if len(d.Type) < 22 {
+				padding = strings.Repeat(" ", 22-len(d.Type))
+			}
+			sb.WriteString(fmt.Sprintf("  %s%s : %d → %d (%s)\n", d.Type, padding, d.Old, d.New, signed(d.Delta)))
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	for _, kind := range diff.Kinds {
+		count := report.GetCountByKind(kind)
+		if count == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s (%d)\n", kindTitles[kind], count))
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, c := range report.Changes {
+			if c.Kind == kind {
+				sb.WriteString(fmt.Sprintf("  %s\n", changeDescription(c)))
+			}
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	return sb.String()
+}
+
+// DiffToJSON exports a snapshot diff to a JSON file.
+func DiffToJSON(report *diff.Report, filePath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// markdownEscape escapes the characters that would break a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "`", "\\`").Replace(s)
+}
+
+// DiffToMarkdown formats a snapshot diff as Markdown, e.g. for release notes
+// or pull request comments.
+func DiffToMarkdown(report *diff.Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Snapshot Diff\n\n")
+	sb.WriteString(fmt.Sprintf("- **Old:** `%s` (%d objects)\n", report.Old, report.OldTotal))
+	sb.WriteString(fmt.Sprintf("- **New:** `%s` (%d objects)\n\n", report.New, report.NewTotal))
+
+	sb.WriteString("| Change | Count |\n")
+	sb.WriteString("|---|---:|\n")
+	for _, c := range report.CountsByKind {
+		sb.WriteString(fmt.Sprintf("| %s | %d |\n", kindTitles[c.Kind], c.Count))
+	}
+	sb.WriteString(fmt.Sprintf("| **Total** | **%d** |\n", report.TotalChanges))
+
+	if len(report.TypeDeltas) > 0 {
+		sb.WriteString("\n## Objects by Type\n\n")
+		sb.WriteString("| Type | Old | New | Delta |\n")
+		sb.WriteString("|---|---:|---:|---:|\n")
+		for _, d := range report.TypeDeltas {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %d | %s |\n", d.Type, d.Old, d.New, signed(d.Delta)))
+		}
+	}
+
+	for _, kind := range diff.Kinds {
+		if report.GetCountByKind(kind) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", kindTitles[kind]))
+		sb.WriteString("| Type | Old ID | New ID | Old Name | New Name | Old File | New File |\n")
+		sb.WriteString("|---|---:|---:|---|---|---|---|\n")
+		for _, c := range report.Changes {
+			if c.Kind != kind {
+				continue
+			}
+			var oldID, newID, oldName, newName string
+			if c.Old != nil {
+				oldID, oldName = c.Old.ID, c.Old.Name
+			}
+			if c.New != nil {
+				newID, newName = c.New.ID, c.New.Name
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				c.Type, oldID, newID, markdownEscape(oldName), markdownEscape(newName),
+				markdownEscape(c.OldPath), markdownEscape(c.NewPath)))
+		}
+	}
+
+	return sb.String()
+}
+
+// DiffToMarkdownFile exports a snapshot diff to a Markdown file.
+func DiffToMarkdownFile(report *diff.Report, filePath string) error {
+	return os.WriteFile(filePath, []byte(DiffToMarkdown(report)), 0644)
+}
+
+// DiffToExcel exports a snapshot diff to an Excel file.
+func DiffToExcel(report *diff.Report, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Create Summary sheet
+	summarySheet := "Summary"
+	f.SetSheetName("Sheet1", summarySheet)
+
+	f.SetCellValue(summarySheet, "A1", "Snapshot Diff")
+	f.SetCellValue(summarySheet, "A2", "Old")
+	f.SetCellValue(summarySheet, "B2", report.Old)
+	f.SetCellValue(summarySheet, "A3", "New")
+	f.SetCellValue(summarySheet, "B3", report.New)
+	f.SetCellValue(summarySheet, "A5", "Change")
+	f.SetCellValue(summarySheet, "B5", "Count")
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "#FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#4472C4"}, Pattern: 1},
+	})
+	f.SetCellStyle(summarySheet, "A5", "B5", headerStyle)
+
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 14},
+	})
+	f.SetCellStyle(summarySheet, "A1", "A1", titleStyle)
+
+	row := 6
+	for _, c := range report.CountsByKind {
+		f.SetCellValue(summarySheet, fmt.Sprintf("A%d", row), kindTitles[c.Kind])
+		f.SetCellValue(summarySheet, fmt.Sprintf("B%d", row), c.Count)
+		row++
+	}
+
+	row++
+	f.SetCellValue(summarySheet, fmt.Sprintf("A%d", row), "TOTAL")
+	f.SetCellValue(summarySheet, fmt.Sprintf("B%d", row), report.TotalChanges)
+	totalStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+	f.SetCellStyle(summarySheet, fmt.Sprintf("A%d", row), fmt.Sprintf("B%d", row), totalStyle)
+
+	f.SetColWidth(summarySheet, "A", "A", 20)
+	f.SetColWidth(summarySheet, "B", "B", 60)
+
+	// Create Types sheet with the per-type deltas
+	typesSheet := "Types"
+	f.NewSheet(typesSheet)
+
+	f.SetCellValue(typesSheet, "A1", "Type")
+	f.SetCellValue(typesSheet, "B1", "Old")
+	f.SetCellValue(typesSheet, "C1", "New")
+	f.SetCellValue(typesSheet, "D1", "Delta")
+	f.SetCellStyle(typesSheet, "A1", "D1", headerStyle)
+
+	for i, d := range report.TypeDeltas {
+		row := i + 2
+		f.SetCellValue(typesSheet, fmt.Sprintf("A%d", row), d.Type)
+		f.SetCellValue(typesSheet, fmt.Sprintf("B%d", row), d.Old)
+		f.SetCellValue(typesSheet, fmt.Sprintf("C%d", row), d.New)
+		f.SetCellValue(typesSheet, fmt.Sprintf("D%d", row), d.Delta)
+	}
+
+	f.SetColWidth(typesSheet, "A", "A", 25)
+	f.SetColWidth(typesSheet, "B", "D", 10)
+
+	// Create Changes sheet
+	changesSheet := "Changes"
+	f.NewSheet(changesSheet)
+
+	f.SetCellValue(changesSheet, "A1", "Change")
+	f.SetCellValue(changesSheet, "B1", "Type")
+	f.SetCellValue(changesSheet, "C1", "Old ID")
+	f.SetCellValue(changesSheet, "D1", "New ID")
+	f.SetCellValue(changesSheet, "E1", "Old Name")
+	f.SetCellValue(changesSheet, "F1", "New Name")
+	f.SetCellValue(changesSheet, "G1", "Old File")
+	f.SetCellValue(changesSheet, "H1", "New File")
+	f.SetCellStyle(changesSheet, "A1", "H1", headerStyle)
+
+	for i, c := range report.Changes {
+		row := i + 2
+		f.SetCellValue(changesSheet, fmt.Sprintf("A%d", row), kindTitles[c.Kind])
+		f.SetCellValue(changesSheet, fmt.Sprintf("B%d", row), c.Type)
+		if c.Old != nil {
+			f.SetCellValue(changesSheet, fmt.Sprintf("C%d", row), c.Old.ID)
+			f.SetCellValue(changesSheet, fmt.Sprintf("E%d", row), c.Old.Name)
+			f.SetCellValue(changesSheet, fmt.Sprintf("G%d", row), c.OldPath)
+		}
+		if c.New != nil {
+			f.SetCellValue(changesSheet, fmt.Sprintf("D%d", row), c.New.ID)
+			f.SetCellValue(changesSheet, fmt.Sprintf("F%d", row), c.New.Name)
+			f.SetCellValue(changesSheet, fmt.Sprintf("H%d", row), c.NewPath)
+		}
+	}
+
+	f.SetColWidth(changesSheet, "A", "B", 15)
+	f.SetColWidth(changesSheet, "C", "D", 10)
+	f.SetColWidth(changesSheet, "E", "F", 35)
+	f.SetColWidth(changesSheet, "G", "H", 50)
+
+	return f.SaveAs(filePath)
+}
@@ -2,11 +2,17 @@ package export
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
 	"github.com/xuri/excelize/v2"
 )
 
+// detailProperties are the object properties shown as columns N onwards in
+// the Details sheet.
+var detailProperties = []string{"Caption", "Access", "Extensible", "PageType", "TableType", "SourceTable", "Subtype"}
+
 // ToExcel exports the summary to an Excel file.
 func ToExcel(summary *counter.Summary, filePath string) error {
 	f := excelize.NewFile()
@@ -16,6 +22,13 @@ func ToExcel(summary *counter.Summary, filePath string) error {
 	summarySheet := "Summary"
 	f.SetSheetName("Sheet1", summarySheet)
 
+	// Sheets named after user data must not collide with the fixed ones
+	usedNames := make(map[string]bool)
+	for _, name := range []string{summarySheet, "Apps", "Namespaces", "Details", "Lines", "Files", "Members",
+		"Duplicates", "Complexity", "Most Complex", "Diagnostics", "Obsolete", "Inactive"} {
+		usedNames[strings.ToLower(name)] = true
+	}
+
 	// Summary headers
 	f.SetCellValue(summarySheet, "A1", "BC Objects Summary")
 	f.SetCellValue(summarySheet, "A3", "Object Type")
@@ -55,6 +68,99 @@ func ToExcel(summary *counter.Summary, filePath string) error {
 	f.SetColWidth(summarySheet, "A", "A", 25)
 	f.SetColWidth(summarySheet, "B", "B", 12)
 
+	// Create Apps sheet and one sheet per app, only when objects belong to apps
+	if len(summary.Apps) > 0 {
+		appsSheet := "Apps"
+		f.NewSheet(appsSheet)
+		f.SetCellValue(appsSheet, "A1", "App")
+		f.SetCellValue(appsSheet, "B1", "ID")
+		f.SetCellValue(appsSheet, "C1", "Publisher")
+		f.SetCellValue(appsSheet, "D1", "Version")
+		f.SetCellValue(appsSheet, "E1", "Objects")
+		f.SetCellValue(appsSheet, "F1", "Code Lines")
+		f.SetCellStyle(appsSheet, "A1", "F1", headerStyle)
+
+		row = 2
+		for _, app := range summary.Apps {
+			name := app.App.Name
+			if app.App == (scanner.AppInfo{}) {
+				name = app.App.Label()
+			}
+			f.SetCellValue(appsSheet, fmt.Sprintf("A%d", row), name)
+			f.SetCellValue(appsSheet, fmt.Sprintf("B%d", row), app.App.ID)
+			f.SetCellValue(appsSheet, fmt.Sprintf("C%d", row), app.App.Publisher)
+			f.SetCellValue(appsSheet, fmt.Sprintf("D%d", row), app.App.Version)
+			f.SetCellValue(appsSheet, fmt.Sprintf("E%d", row), app.TotalObjects)
+			f.SetCellValue(appsSheet, fmt.Sprintf("F%d", row), app.Lines.Code)
+			row++
+
+			appSheet := uniqueSheetName("App "+name, usedNames)
+			f.NewSheet(appSheet)
+			f.SetCellValue(appSheet, "A1", app.App.Label())
+			f.SetCellStyle(appSheet, "A1", "A1", titleStyle)
+			f.SetCellValue(appSheet, "A3", "Object Type")
+			f.SetCellValue(appSheet, "B3", "Count")
+			f.SetCellStyle(appSheet, "A3", "B3", headerStyle)
+
+			appRow := 4
+			for _, c := range app.CountsByType {
+				f.SetCellValue(appSheet, fmt.Sprintf("A%d", appRow), c.Type)
+				f.SetCellValue(appSheet, fmt.Sprintf("B%d", appRow), c.Count)
+				appRow++
+			}
+			appRow++
+			f.SetCellValue(appSheet, fmt.Sprintf("A%d", appRow), "TOTAL")
+			f.SetCellValue(appSheet, fmt.Sprintf("B%d", appRow), app.TotalObjects)
+			f.SetCellStyle(appSheet, fmt.Sprintf("A%d", appRow), fmt.Sprintf("B%d", appRow), totalStyle)
+
+			f.SetColWidth(appSheet, "A", "A", 25)
+			f.SetColWidth(appSheet, "B", "B", 12)
+		}
+
+		f.SetColWidth(appsSheet, "A", "A", 40)
+		f.SetColWidth(appsSheet, "B", "B", 38)
+		f.SetColWidth(appsSheet, "C", "C", 25)
+		f.SetColWidth(appsSheet, "D", "F", 12)
+	}
+
+	// Create Namespaces sheet
+	namespacesSheet := "Namespaces"
+	f.NewSheet(namespacesSheet)
+	f.SetCellValue(namespacesSheet, "A1", "Namespace")
+	f.SetCellValue(namespacesSheet, "B1", "Count")
+	f.SetCellStyle(namespacesSheet, "A1", "B1", headerStyle)
+
+	row = 2
+	for _, c := range summary.CountsByNamespace {
+		f.SetCellValue(namespacesSheet, fmt.Sprintf("A%d", row), namespaceLabel(c.Namespace))
+		f.SetCellValue(namespacesSheet, fmt.Sprintf("B%d", row), c.Count)
+		row++
+	}
+
+	f.SetColWidth(namespacesSheet, "A", "A", 40)
+	f.SetColWidth(namespacesSheet, "B", "B", 12)
+
+	// Create a sheet for the property breakdown (--group-by)
+	if summary.ByProperty != nil {
+		propertySheet := uniqueSheetName("By "+summary.ByProperty.Property, usedNames)
+		if _, err := f.NewSheet(propertySheet); err != nil {
+			return fmt.Errorf("creating sheet %q: %w", propertySheet, err)
+		}
+		f.SetCellValue(propertySheet, "A1", summary.ByProperty.Property)
+		f.SetCellValue(propertySheet, "B1", "Count")
+		f.SetCellStyle(propertySheet, "A1", "B1", headerStyle)
+
+		row = 2
+		for _, c := range summary.ByProperty.Counts {
+			f.SetCellValue(propertySheet, fmt.Sprintf("A%d", row), propertyLabel(c.Value))
+			f.SetCellValue(propertySheet, fmt.Sprintf("B%d", row), c.Count)
+			row++
+		}
+
+		f.SetColWidth(propertySheet, "A", "A", 40)
+		f.SetColWidth(propertySheet, "B", "B", 12)
+	}
+
 	// Create Details sheet
 	detailsSheet := "Details"
 	f.NewSheet(detailsSheet)
@@ -63,8 +169,24 @@ func ToExcel(summary *counter.Summary, filePath string) error {
 	f.SetCellValue(detailsSheet, "A1", "Type")
 	f.SetCellValue(detailsSheet, "B1", "ID")
 	f.SetCellValue(detailsSheet, "C1", "Name")
-	f.SetCellValue(detailsSheet, "D1", "File Path")
-	f.SetCellStyle(detailsSheet, "A1", "D1", headerStyle)
+	f.SetCellValue(detailsSheet, "D1", "Namespace")
+	f.SetCellValue(detailsSheet, "E1", "Qualified Name")
+	f.SetCellValue(detailsSheet, "F1", "Extends")
+	f.SetCellValue(detailsSheet, "G1", "Implements")
+	f.SetCellValue(detailsSheet, "H1", "File Path")
+	f.SetCellValue(detailsSheet, "I1", "Line")
+	f.SetCellValue(detailsSheet, "J1", "Column")
+	f.SetCellValue(detailsSheet, "K1", "End Line")
+	f.SetCellValue(detailsSheet, "L1", "Version List")
+	f.SetCellValue(detailsSheet, "M1", "Modified")
+	for i, name := range detailProperties {
+		f.SetCellValue(detailsSheet, fmt.Sprintf("%c1", 'N'+i), name)
+	}
+	f.SetCellValue(detailsSheet, "U1", "Physical Lines")
+	f.SetCellValue(detailsSheet, "V1", "Code Lines")
+	f.SetCellValue(detailsSheet, "W1", "Comment Lines")
+	f.SetCellValue(detailsSheet, "X1", "Blank Lines")
+	f.SetCellStyle(detailsSheet, "A1", "X1", headerStyle)
 
 	// Write all objects
 	row = 2
@@ -72,7 +194,28 @@ func ToExcel(summary *counter.Summary, filePath string) error {
 		f.SetCellValue(detailsSheet, fmt.Sprintf("A%d", row), obj.Type)
 		f.SetCellValue(detailsSheet, fmt.Sprintf("B%d", row), obj.ID)
 		f.SetCellValue(detailsSheet, fmt.Sprintf("C%d", row), obj.Name)
-		f.SetCellValue(detailsSheet, fmt.Sprintf("D%d", row), obj.FilePath)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("D%d", row), obj.Namespace)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("E%d", row), obj.QualifiedName)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("F%d", row), obj.Extends)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("G%d", row), strings.Join(obj.Implements, ", "))
+		f.SetCellValue(detailsSheet, fmt.Sprintf("H%d", row), obj.FilePath)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("I%d", row), obj.Line)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("J%d", row), obj.Column)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("K%d", row), obj.EndLine)
+		// C/AL objects carry their legacy object properties
+		if obj.CAL != nil {
+			f.SetCellValue(detailsSheet, fmt.Sprintf("L%d", row), obj.CAL.VersionList)
+			f.SetCellValue(detailsSheet, fmt.Sprintf("M%d", row), obj.CAL.Modified)
+		}
+		for i, name := range detailProperties {
+			if value, ok := obj.Properties.Lookup(name); ok {
+				f.SetCellValue(detailsSheet, fmt.Sprintf("%c%d", 'N'+i, row), value)
+			}
+		}
+		f.SetCellValue(detailsSheet, fmt.Sprintf("U%d", row), obj.Lines.Physical)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("V%d", row), obj.Lines.Code)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("W%d", row), obj.Lines.Comment)
+		f.SetCellValue(detailsSheet, fmt.Sprintf("X%d", row), obj.Lines.Blank)
 		row++
 	}
 
@@ -80,7 +223,341 @@ func ToExcel(summary *counter.Summary, filePath string) error {
 	f.SetColWidth(detailsSheet, "A", "A", 20)
 	f.SetColWidth(detailsSheet, "B", "B", 10)
 	f.SetColWidth(detailsSheet, "C", "C", 40)
-	f.SetColWidth(detailsSheet, "D", "D", 60)
+	f.SetColWidth(detailsSheet, "D", "D", 30)
+	f.SetColWidth(detailsSheet, "E", "E", 50)
+	f.SetColWidth(detailsSheet, "F", "F", 30)
+	f.SetColWidth(detailsSheet, "G", "G", 30)
+	f.SetColWidth(detailsSheet, "H", "H", 60)
+	f.SetColWidth(detailsSheet, "I", "K", 10)
+	f.SetColWidth(detailsSheet, "L", "L", 30)
+	f.SetColWidth(detailsSheet, "M", "M", 10)
+	f.SetColWidth(detailsSheet, "N", "N", 40)
+	f.SetColWidth(detailsSheet, "O", "T", 15)
+	f.SetColWidth(detailsSheet, "U", "X", 14)
+
+	// Create Lines sheet with line counts per object type and the totals
+	linesSheet := "Lines"
+	f.NewSheet(linesSheet)
+	f.SetCellValue(linesSheet, "A1", "Object Type")
+	f.SetCellValue(linesSheet, "B1", "Physical")
+	f.SetCellValue(linesSheet, "C1", "Code")
+	f.SetCellValue(linesSheet, "D1", "Comment")
+	f.SetCellValue(linesSheet, "E1", "Blank")
+	f.SetCellStyle(linesSheet, "A1", "E1", headerStyle)
+
+	row = 2
+	for _, tl := range summary.LinesByType {
+		setLineCounts(f, linesSheet, row, tl.Type, tl.Lines)
+		row++
+	}
+	row++
+	setLineCounts(f, linesSheet, row, "TOTAL", summary.Lines)
+	f.SetCellStyle(linesSheet, fmt.Sprintf("A%d", row), fmt.Sprintf("E%d", row), totalStyle)
+
+	f.SetColWidth(linesSheet, "A", "A", 25)
+	f.SetColWidth(linesSheet, "B", "E", 12)
+
+	// Create Files sheet with line counts per scanned file
+	if len(summary.Files) > 0 {
+		filesSheet := "Files"
+		f.NewSheet(filesSheet)
+		f.SetCellValue(filesSheet, "A1", "File Path")
+		f.SetCellValue(filesSheet, "B1", "Physical")
+		f.SetCellValue(filesSheet, "C1", "Code")
+		f.SetCellValue(filesSheet, "D1", "Comment")
+		f.SetCellValue(filesSheet, "E1", "Blank")
+		f.SetCellStyle(filesSheet, "A1", "E1", headerStyle)
+
+		row = 2
+		for _, fm := range summary.Files {
+			setLineCounts(f, filesSheet, row, fm.Path, fm.Lines)
+			row++
+		}
+
+		f.SetColWidth(filesSheet, "A", "A", 60)
+		f.SetColWidth(filesSheet, "B", "E", 12)
+	}
+
+	// Create Members sheet with member counts per object, one column per kind
+	membersSheet := "Members"
+	f.NewSheet(membersSheet)
+	f.SetCellValue(membersSheet, "A1", "Type")
+	f.SetCellValue(membersSheet, "B1", "ID")
+	f.SetCellValue(membersSheet, "C1", "Name")
+	for i, kind := range scanner.MemberKinds {
+		f.SetCellValue(membersSheet, fmt.Sprintf("%c1", 'D'+i), kind)
+	}
+	totalCol := 'D' + len(scanner.MemberKinds)
+	f.SetCellValue(membersSheet, fmt.Sprintf("%c1", totalCol), "Total")
+	f.SetCellStyle(membersSheet, "A1", fmt.Sprintf("%c1", totalCol), headerStyle)
+
+	row = 2
+	for _, obj := range summary.Objects {
+		f.SetCellValue(membersSheet, fmt.Sprintf("A%d", row), obj.Type)
+		f.SetCellValue(membersSheet, fmt.Sprintf("B%d", row), obj.ID)
+		f.SetCellValue(membersSheet, fmt.Sprintf("C%d", row), obj.Name)
+		for i, kind := range scanner.MemberKinds {
+			f.SetCellValue(membersSheet, fmt.Sprintf("%c%d", 'D'+i, row), obj.CountMembers(kind))
+		}
+		f.SetCellValue(membersSheet, fmt.Sprintf("%c%d", totalCol, row), len(obj.Members))
+		row++
+	}
+
+	f.SetColWidth(membersSheet, "A", "A", 20)
+	f.SetColWidth(membersSheet, "B", "B", 10)
+	f.SetColWidth(membersSheet, "C", "C", 40)
+	f.SetColWidth(membersSheet, "D", fmt.Sprintf("%c", totalCol), 12)
+
+	// Create Duplicates sheet with one row per declaration
+	if len(summary.Duplicates) > 0 {
+		duplicatesSheet := "Duplicates"
+		f.NewSheet(duplicatesSheet)
+		f.SetCellValue(duplicatesSheet, "A1", "Duplicate")
+		f.SetCellValue(duplicatesSheet, "B1", "Type")
+		f.SetCellValue(duplicatesSheet, "C1", "Value")
+		f.SetCellValue(duplicatesSheet, "D1", "App")
+		f.SetCellValue(duplicatesSheet, "E1", "Object ID")
+		f.SetCellValue(duplicatesSheet, "F1", "Object Name")
+		f.SetCellValue(duplicatesSheet, "G1", "File Path")
+		f.SetCellValue(duplicatesSheet, "H1", "Line")
+		f.SetCellStyle(duplicatesSheet, "A1", "H1", headerStyle)
+
+		row = 2
+		for _, d := range summary.Duplicates {
+			for _, obj := range d.Objects {
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("A%d", row), duplicateKindLabel(d.Kind))
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("B%d", row), d.Type)
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("C%d", row), d.Value)
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("D%d", row), d.App.Label())
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("E%d", row), obj.ID)
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("F%d", row), obj.Name)
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("G%d", row), obj.FilePath)
+				f.SetCellValue(duplicatesSheet, fmt.Sprintf("H%d", row), obj.Line)
+				row++
+			}
+		}
+
+		f.SetColWidth(duplicatesSheet, "A", "A", 12)
+		f.SetColWidth(duplicatesSheet, "B", "B", 20)
+		f.SetColWidth(duplicatesSheet, "C", "C", 40)
+		f.SetColWidth(duplicatesSheet, "D", "D", 30)
+		f.SetColWidth(duplicatesSheet, "E", "E", 10)
+		f.SetColWidth(duplicatesSheet, "F", "F", 40)
+		f.SetColWidth(duplicatesSheet, "G", "G", 60)
+		f.SetColWidth(duplicatesSheet, "H", "H", 10)
+	}
+
+	// Create Complexity sheets: the rollup per object and the top routines
+	if len(summary.ComplexityByObject) > 0 {
+		complexitySheet := "Complexity"
+		f.NewSheet(complexitySheet)
+		f.SetCellValue(complexitySheet, "A1", "Type")
+		f.SetCellValue(complexitySheet, "B1", "ID")
+		f.SetCellValue(complexitySheet, "C1", "Name")
+		f.SetCellValue(complexitySheet, "D1", "Routines")
+		f.SetCellValue(complexitySheet, "E1", "Total Complexity")
+		f.SetCellValue(complexitySheet, "F1", "Max Complexity")
+		f.SetCellValue(complexitySheet, "G1", "Max Nesting")
+		f.SetCellValue(complexitySheet, "H1", "File Path")
+		f.SetCellStyle(complexitySheet, "A1", "H1", headerStyle)
+
+		row = 2
+		for _, oc := range summary.ComplexityByObject {
+			f.SetCellValue(complexitySheet, fmt.Sprintf("A%d", row), oc.Type)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("B%d", row), oc.ID)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("C%d", row), oc.Name)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("D%d", row), oc.Routines)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("E%d", row), oc.Total)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("F%d", row), oc.Max)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("G%d", row), oc.MaxNesting)
+			f.SetCellValue(complexitySheet, fmt.Sprintf("H%d", row), oc.FilePath)
+			row++
+		}
+
+		f.SetColWidth(complexitySheet, "A", "A", 20)
+		f.SetColWidth(complexitySheet, "B", "B", 10)
+		f.SetColWidth(complexitySheet, "C", "C", 40)
+		f.SetColWidth(complexitySheet, "D", "G", 15)
+		f.SetColWidth(complexitySheet, "H", "H", 60)
+	}
+
+	if len(summary.MostComplex) > 0 {
+		topSheet := "Most Complex"
+		f.NewSheet(topSheet)
+		f.SetCellValue(topSheet, "A1", "Complexity")
+		f.SetCellValue(topSheet, "B1", "Nesting")
+		f.SetCellValue(topSheet, "C1", "Object Type")
+		f.SetCellValue(topSheet, "D1", "Object ID")
+		f.SetCellValue(topSheet, "E1", "Object Name")
+		f.SetCellValue(topSheet, "F1", "Kind")
+		f.SetCellValue(topSheet, "G1", "Name")
+		f.SetCellValue(topSheet, "H1", "File Path")
+		f.SetCellValue(topSheet, "I1", "Line")
+		f.SetCellStyle(topSheet, "A1", "I1", headerStyle)
+
+		row = 2
+		for _, r := range summary.MostComplex {
+			f.SetCellValue(topSheet, fmt.Sprintf("A%d", row), r.Complexity)
+			f.SetCellValue(topSheet, fmt.Sprintf("B%d", row), r.Nesting)
+			f.SetCellValue(topSheet, fmt.Sprintf("C%d", row), r.ObjectType)
+			f.SetCellValue(topSheet, fmt.Sprintf("D%d", row), r.ObjectID)
+			f.SetCellValue(topSheet, fmt.Sprintf("E%d", row), r.ObjectName)
+			f.SetCellValue(topSheet, fmt.Sprintf("F%d", row), r.Kind)
+			f.SetCellValue(topSheet, fmt.Sprintf("G%d", row), r.Name)
+			f.SetCellValue(topSheet, fmt.Sprintf("H%d", row), r.FilePath)
+			f.SetCellValue(topSheet, fmt.Sprintf("I%d", row), r.Line)
+			row++
+		}
+
+		f.SetColWidth(topSheet, "A", "B", 12)
+		f.SetColWidth(topSheet, "C", "C", 20)
+		f.SetColWidth(topSheet, "D", "D", 10)
+		f.SetColWidth(topSheet, "E", "E", 40)
+		f.SetColWidth(topSheet, "F", "F", 12)
+		f.SetColWidth(topSheet, "G", "G", 40)
+		f.SetColWidth(topSheet, "H", "H", 60)
+		f.SetColWidth(topSheet, "I", "I", 10)
+	}
+
+	// Create Diagnostics sheet
+	diagnosticsSheet := "Diagnostics"
+	f.NewSheet(diagnosticsSheet)
+	f.SetCellValue(diagnosticsSheet, "A1", "Severity")
+	f.SetCellValue(diagnosticsSheet, "B1", "Code")
+	f.SetCellValue(diagnosticsSheet, "C1", "File")
+	f.SetCellValue(diagnosticsSheet, "D1", "Line")
+	f.SetCellValue(diagnosticsSheet, "E1", "Column")
+	f.SetCellValue(diagnosticsSheet, "F1", "Message")
+	f.SetCellStyle(diagnosticsSheet, "A1", "F1", headerStyle)
+
+	row = 2
+	for _, d := range summary.Diagnostics {
+		f.SetCellValue(diagnosticsSheet, fmt.Sprintf("A%d", row), string(d.Severity))
+		f.SetCellValue(diagnosticsSheet, fmt.Sprintf("B%d", row), d.Code)
+		f.SetCellValue(diagnosticsSheet, fmt.Sprintf("C%d", row), d.File)
+		f.SetCellValue(diagnosticsSheet, fmt.Sprintf("D%d", row), d.Line)
+		f.SetCellValue(diagnosticsSheet, fmt.Sprintf("E%d", row), d.Column)
+		f.SetCellValue(diagnosticsSheet, fmt.Sprintf("F%d", row), d.Message)
+		row++
+	}
+
+	f.SetColWidth(diagnosticsSheet, "A", "B", 15)
+	f.SetColWidth(diagnosticsSheet, "C", "C", 60)
+	f.SetColWidth(diagnosticsSheet, "D", "E", 10)
+	f.SetColWidth(diagnosticsSheet, "F", "F", 60)
+
+	// Create Obsolete sheet for objects and members pending removal
+	if len(summary.Obsolete) > 0 {
+		obsoleteSheet := "Obsolete"
+		f.NewSheet(obsoleteSheet)
+		f.SetCellValue(obsoleteSheet, "A1", "Obsolete Tag")
+		f.SetCellValue(obsoleteSheet, "B1", "State")
+		f.SetCellValue(obsoleteSheet, "C1", "Object Type")
+		f.SetCellValue(obsoleteSheet, "D1", "Object ID")
+		f.SetCellValue(obsoleteSheet, "E1", "Object Name")
+		f.SetCellValue(obsoleteSheet, "F1", "Kind")
+		f.SetCellValue(obsoleteSheet, "G1", "Member ID")
+		f.SetCellValue(obsoleteSheet, "H1", "Member Name")
+		f.SetCellValue(obsoleteSheet, "I1", "Reason")
+		f.SetCellValue(obsoleteSheet, "J1", "File Path")
+		f.SetCellValue(obsoleteSheet, "K1", "Line")
+		f.SetCellStyle(obsoleteSheet, "A1", "K1", headerStyle)
+
+		row = 2
+		for _, g := range summary.Obsolete {
+			for _, e := range g.Entries {
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("A%d", row), obsoleteTagLabel(e.Tag))
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("B%d", row), e.State)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("C%d", row), e.ObjectType)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("D%d", row), e.ObjectID)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("E%d", row), e.ObjectName)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("F%d", row), e.Kind)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("G%d", row), e.MemberID)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("H%d", row), e.MemberName)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("I%d", row), e.Reason)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("J%d", row), e.FilePath)
+				f.SetCellValue(obsoleteSheet, fmt.Sprintf("K%d", row), e.Line)
+				row++
+			}
+		}
+
+		f.SetColWidth(obsoleteSheet, "A", "B", 12)
+		f.SetColWidth(obsoleteSheet, "C", "C", 20)
+		f.SetColWidth(obsoleteSheet, "D", "D", 10)
+		f.SetColWidth(obsoleteSheet, "E", "E", 40)
+		f.SetColWidth(obsoleteSheet, "F", "G", 12)
+		f.SetColWidth(obsoleteSheet, "H", "H", 30)
+		f.SetColWidth(obsoleteSheet, "I", "I", 50)
+		f.SetColWidth(obsoleteSheet, "J", "J", 60)
+		f.SetColWidth(obsoleteSheet, "K", "K", 10)
+	}
+
+	// Create Inactive sheet for objects excluded by the preprocessor
+	if len(summary.InactiveObjects) > 0 {
+		inactiveSheet := "Inactive"
+		f.NewSheet(inactiveSheet)
+		f.SetCellValue(inactiveSheet, "A1", "Type")
+		f.SetCellValue(inactiveSheet, "B1", "ID")
+		f.SetCellValue(inactiveSheet, "C1", "Name")
+		f.SetCellValue(inactiveSheet, "D1", "File Path")
+		f.SetCellValue(inactiveSheet, "E1", "Line")
+		f.SetCellStyle(inactiveSheet, "A1", "E1", headerStyle)
+
+		row = 2
+		for _, obj := range summary.InactiveObjects {
+			f.SetCellValue(inactiveSheet, fmt.Sprintf("A%d", row), obj.Type)
+			f.SetCellValue(inactiveSheet, fmt.Sprintf("B%d", row), obj.ID)
+			f.SetCellValue(inactiveSheet, fmt.Sprintf("C%d", row), obj.Name)
+			f.SetCellValue(inactiveSheet, fmt.Sprintf("D%d", row), obj.FilePath)
+			f.SetCellValue(inactiveSheet, fmt.Sprintf("E%d", row), obj.Line)
+			row++
+		}
+
+		f.SetColWidth(inactiveSheet, "A", "A", 20)
+		f.SetColWidth(inactiveSheet, "B", "B", 10)
+		f.SetColWidth(inactiveSheet, "C", "C", 40)
+		f.SetColWidth(inactiveSheet, "D", "D", 60)
+		f.SetColWidth(inactiveSheet, "E", "E", 10)
+	}
 
 	return f.SaveAs(filePath)
 }
+
+// setLineCounts writes a label and its line counts to columns A to E of row.
+func setLineCounts(f *excelize.File, sheet string, row int, label string, lines scanner.LineCounts) {
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", row), label)
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", row), lines.Physical)
+	f.SetCellValue(sheet, fmt.Sprintf("C%d", row), lines.Code)
+	f.SetCellValue(sheet, fmt.Sprintf("D%d", row), lines.Comment)
+	f.SetCellValue(sheet, fmt.Sprintf("E%d", row), lines.Blank)
+}
+
+// uniqueSheetName turns name into a valid sheet name that is not in used
+// (lowercased, as Excel compares sheet names case-insensitively), by replacing
+// characters Excel does not allow, truncating it to 31 characters and
+// numbering duplicates. The result is added to used.
+func uniqueSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	candidate := truncateRunes(name, 31)
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		candidate = truncateRunes(name, 31-len(suffix)) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// truncateRunes shortens s to at most n runes.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -1,13 +1,20 @@
 package export
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/andrijan/bc-objects-counter/internal/check"
+	"github.com/andrijan/bc-objects-counter/internal/conflict"
 	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/diff"
+	"github.com/andrijan/bc-objects-counter/internal/ids"
+	"github.com/andrijan/bc-objects-counter/internal/migrate"
 	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/xuri/excelize/v2"
 )
 
 func createTestSummary() *counter.Summary {
@@ -144,3 +151,729 @@ func TestToConsoleEmpty(t *testing.T) {
 		t.Error("console output should contain 0 for empty summary")
 	}
 }
+
+func TestToJSONStringExtendsAndImplements(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "tableextension", ID: "50100", Name: "Cust Ext", Extends: "Customer", FilePath: "src/ext.al"},
+		{Type: "codeunit", ID: "50101", Name: "Impl", Implements: []string{"IShipping"}, FilePath: "src/impl.al"},
+	}
+	summary := counter.CountObjects(objects)
+
+	jsonStr, err := ToJSONString(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(jsonStr, `"extends": "Customer"`) {
+		t.Error("JSON should contain the extends target")
+	}
+	if !strings.Contains(jsonStr, `"interface": "IShipping"`) {
+		t.Error("JSON should contain the implemented interface")
+	}
+}
+
+func TestToConsoleNamespaces(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Budget", Namespace: "Contoso.Sales", QualifiedName: "Contoso.Sales.Budget"},
+		{Type: "codeunit", ID: "50100", Name: "Legacy", QualifiedName: "Legacy"},
+	}
+	summary := counter.CountObjects(objects)
+
+	output := ToConsole(summary)
+
+	if !strings.Contains(output, "By Namespace") {
+		t.Error("console output should contain namespace section")
+	}
+	if !strings.Contains(output, "Contoso.Sales") {
+		t.Error("console output should contain Contoso.Sales namespace")
+	}
+	if !strings.Contains(output, "(no namespace)") {
+		t.Error("console output should contain placeholder for objects without namespace")
+	}
+
+	// Summaries without namespaces keep the compact layout
+	if strings.Contains(ToConsole(createTestSummary()), "By Namespace") {
+		t.Error("console output should omit namespace section when no namespaces are used")
+	}
+}
+
+func TestToConsoleDiagnostics(t *testing.T) {
+	summary := createTestSummary()
+	summary.Diagnostics = []scanner.Diagnostic{
+		{File: "bad.al", Severity: scanner.SeverityError, Code: scanner.CodeReadError, Message: "permission denied"},
+	}
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "Diagnostics: 1 error(s), 0 warning(s)") {
+		t.Error("console output should contain diagnostics count")
+	}
+
+	listing := FormatDiagnostics(summary.Diagnostics)
+	if !strings.Contains(listing, "bad.al: error: permission denied [read-error]") {
+		t.Errorf("unexpected diagnostics listing: %q", listing)
+	}
+
+	// Diagnostics must also end up in the exported files
+	tmpDir := t.TempDir()
+	if err := ToExcel(summary, filepath.Join(tmpDir, "diag.xlsx")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ToPDF(summary, filepath.Join(tmpDir, "diag.pdf")); err != nil {
+		t.Fatal(err)
+	}
+	jsonStr, err := ToJSONString(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(jsonStr, `"code": "read-error"`) {
+		t.Error("JSON should contain the diagnostic")
+	}
+}
+
+func TestToConsoleInactiveObjects(t *testing.T) {
+	summary := createTestSummary()
+	summary.InactiveObjects = []scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Old Table", FilePath: "/test/OldTable.al", Line: 3},
+	}
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "Inactive (not counted): 1") || !strings.Contains(output, "Old Table") {
+		t.Errorf("console output should list inactive objects, got %q", output)
+	}
+	if strings.Contains(ToConsole(createTestSummary()), "Inactive") {
+		t.Error("console output should omit the inactive section when there are none")
+	}
+
+	tmpDir := t.TempDir()
+	if err := ToExcel(summary, filepath.Join(tmpDir, "inactive.xlsx")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ToPDF(summary, filepath.Join(tmpDir, "inactive.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportPropertyBreakdown(t *testing.T) {
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "page", ID: "50100", Name: "Customer API", Properties: scanner.Properties{"PageType": "API", "Caption": "Customers"}},
+		{Type: "page", ID: "50101", Name: "Setup"},
+	})
+	summary.GroupBy("PageType")
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "By PageType") || !strings.Contains(output, "(not set) : 1") {
+		t.Errorf("console output should contain the property breakdown, got %q", output)
+	}
+
+	jsonStr, err := ToJSONString(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(jsonStr, `"PageType": "API"`) || !strings.Contains(jsonStr, `"property": "PageType"`) {
+		t.Error("JSON should contain object properties and the breakdown")
+	}
+
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "properties.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if header, _ := f.GetCellValue("Details", "Q1"); header != "PageType" {
+		t.Errorf("expected PageType header in Q1, got %q", header)
+	}
+	if value, _ := f.GetCellValue("Details", "Q2"); value != "API" {
+		t.Errorf("expected API in Q2, got %q", value)
+	}
+	if value, _ := f.GetCellValue("By PageType", "A3"); value != "API" {
+		t.Errorf("expected API in breakdown row 3, got %q", value)
+	}
+
+	if err := ToPDF(summary, filepath.Join(tmpDir, "properties.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportObsolete(t *testing.T) {
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Old Setup",
+			Obsolete: &scanner.Obsolete{State: "Pending", Reason: "Replaced", Tag: "24.0"},
+			ObsoleteMembers: []scanner.ObsoleteMember{
+				{Kind: scanner.MemberField, ID: "2", Name: "Legacy Code", Obsolete: scanner.Obsolete{State: "Removed", Tag: "23.0"}},
+			}},
+	})
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "Obsolete by Tag (2)") || !strings.Contains(output, "24.0 : 1 (1 pending, 0 removed)") {
+		t.Errorf("console output should contain the obsolete section, got %q", output)
+	}
+	if strings.Contains(ToConsole(createTestSummary()), "Obsolete") {
+		t.Error("console output should omit the obsolete section when nothing is obsolete")
+	}
+
+	jsonStr, err := ToJSONString(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(jsonStr, `"memberName": "Legacy Code"`) {
+		t.Error("JSON should contain the obsolete report")
+	}
+
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "obsolete.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Obsolete", "H2"); value != "Legacy Code" {
+		t.Errorf("expected Legacy Code in H2, got %q", value)
+	}
+
+	if err := ToPDF(summary, filepath.Join(tmpDir, "obsolete.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportMembers(t *testing.T) {
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Setup", Members: []scanner.Member{
+			{Kind: scanner.MemberField, ID: "1", Name: "Primary Key"},
+			{Kind: scanner.MemberField, ID: "2", Name: "Status"},
+			{Kind: scanner.MemberProcedure, Name: "GetSetup", Access: "public"},
+		}},
+	})
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "field     : 2") || !strings.Contains(output, "TOTAL     : 3") {
+		t.Errorf("console output should contain member totals, got %q", output)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "members.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if header, _ := f.GetCellValue("Members", "D1"); header != scanner.MemberField {
+		t.Errorf("expected field header in D1, got %q", header)
+	}
+	if value, _ := f.GetCellValue("Members", "D2"); value != "2" {
+		t.Errorf("expected 2 fields in D2, got %q", value)
+	}
+	if value, _ := f.GetCellValue("Members", "K2"); value != "3" {
+		t.Errorf("expected 3 members in K2, got %q", value)
+	}
+}
+
+func TestMigrationExports(t *testing.T) {
+	report := migrate.Compare(
+		[]scanner.BCObject{
+			{Type: "table", ID: "18", Name: "Customer", CAL: &scanner.CALProperties{Modified: true}},
+			{Type: "codeunit", ID: "50000", Name: "Old Logic", CAL: &scanner.CALProperties{Modified: true}},
+		},
+		[]scanner.BCObject{
+			{Type: "tableextension", ID: "50100", Name: "Customer Ext", Extends: "Customer"},
+		},
+	)
+
+	output := MigrationToConsole(report)
+	if !strings.Contains(output, "C/AL Migration Status") {
+		t.Error("console output should contain title")
+	}
+	if !strings.Contains(output, "Old Logic") {
+		t.Error("console output should list missing objects")
+	}
+
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "migration.json")
+	if err := MigrationToJSON(report, jsonFile); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"status": "partial"`) {
+		t.Error("JSON should contain the partial status")
+	}
+
+	for _, name := range []string{"migration.xlsx", "migration.pdf"} {
+		path := filepath.Join(tmpDir, name)
+		var err error
+		if strings.HasSuffix(name, ".xlsx") {
+			err = MigrationToExcel(report, path)
+		} else {
+			err = MigrationToPDF(report, path)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s was not written", name)
+		}
+	}
+}
+
+func TestExportLines(t *testing.T) {
+	summary := counter.Summarize(&scanner.Result{
+		Findings: scanner.Findings{Objects: []scanner.BCObject{
+			{Type: "codeunit", ID: "50100", Name: "Posting", Lines: scanner.LineCounts{Physical: 12, Code: 9, Comment: 2, Blank: 1}},
+		}},
+		Files: []scanner.FileMetrics{
+			{Path: "Posting.Codeunit.al", Lines: scanner.LineCounts{Physical: 14, Code: 9, Comment: 3, Blank: 2}},
+		},
+	})
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "Lines of Code") || !strings.Contains(output, "code     : 9") || !strings.Contains(output, "codeunit : 9 code lines") {
+		t.Errorf("console output should contain line counts, got %q", output)
+	}
+	if strings.Contains(ToConsole(createTestSummary()), "Lines of Code") {
+		t.Error("console output should not contain line counts without scanned lines")
+	}
+
+	filePath := filepath.Join(t.TempDir(), "lines.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Details", "V2"); value != "9" {
+		t.Errorf("expected 9 code lines in V2, got %q", value)
+	}
+	if value, _ := f.GetCellValue("Lines", "B4"); value != "14" {
+		t.Errorf("expected 14 physical lines in the total row, got %q", value)
+	}
+	if value, _ := f.GetCellValue("Files", "A2"); value != "Posting.Codeunit.al" {
+		t.Errorf("expected file path in A2, got %q", value)
+	}
+
+	if err := ToPDF(summary, filepath.Join(t.TempDir(), "lines.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportComplexity(t *testing.T) {
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Posting", FilePath: "Posting.al", Members: []scanner.Member{
+			{Kind: scanner.MemberProcedure, Name: "Post", Complexity: 12, Nesting: 3, Line: 10},
+			{Kind: scanner.MemberTrigger, Name: "OnRun", Complexity: 1, Line: 4},
+		}},
+	})
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "Most Complex (top 2)") || !strings.Contains(output, " 12  codeunit 50100 Posting :: Post (nesting 3)") {
+		t.Errorf("console output should list the most complex routines, got %q", output)
+	}
+
+	listing := FormatComplexityViolations(summary.ExceedingComplexity(10), 10)
+	if listing != "Posting.al:10: complexity 12 exceeds 10: codeunit 50100 Posting :: Post\n" {
+		t.Errorf("unexpected violation listing %q", listing)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "complexity.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Complexity", "E2"); value != "13" {
+		t.Errorf("expected total complexity 13 in E2, got %q", value)
+	}
+	if value, _ := f.GetCellValue("Most Complex", "G2"); value != "Post" {
+		t.Errorf("expected Post in G2, got %q", value)
+	}
+
+	if err := ToPDF(summary, filepath.Join(t.TempDir(), "complexity.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportApps(t *testing.T) {
+	sales := &scanner.AppInfo{ID: "1", Name: "Sales", Publisher: "Contoso", Version: "1.0.0.0"}
+	tests := &scanner.AppInfo{ID: "2", Name: "Sales: Tests/PTE", Publisher: "Contoso", Version: "1.0.0.0"}
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "table", ID: "50100", Name: "Sales Setup", App: sales},
+		{Type: "page", ID: "50100", Name: "Sales Setup", App: sales},
+		{Type: "codeunit", ID: "50150", Name: "Sales Tests", App: tests},
+	})
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "App: Sales (Contoso) 1.0.0.0") || !strings.Contains(output, "App: Sales: Tests/PTE (Contoso) 1.0.0.0") {
+		t.Errorf("console output should contain a section per app, got %q", output)
+	}
+	if strings.Contains(ToConsole(createTestSummary()), "App:") {
+		t.Error("console output should not contain app sections without apps")
+	}
+
+	filePath := filepath.Join(t.TempDir(), "apps.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Apps", "E2"); value != "2" {
+		t.Errorf("expected 2 objects for Sales in E2, got %q", value)
+	}
+	if value, _ := f.GetCellValue("App Sales", "B7"); value != "2" {
+		t.Errorf("expected TOTAL 2 on the Sales sheet, got %q", value)
+	}
+	if value, _ := f.GetCellValue("App Sales_ Tests_PTE", "A4"); value != "codeunit" {
+		t.Errorf("expected sanitized sheet for the test app, got %q", value)
+	}
+
+	if err := ToPDF(summary, filepath.Join(t.TempDir(), "apps.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportDuplicates(t *testing.T) {
+	app := &scanner.AppInfo{ID: "1", Name: "Sales"}
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "codeunit", ID: "50100", Name: "Post Sales", FilePath: "Post.al", Line: 1, App: app},
+		{Type: "codeunit", ID: "50100", Name: "Release Sales", FilePath: "Release.al", Line: 1, App: app},
+	})
+
+	output := ToConsole(summary)
+	if !strings.Contains(output, "Duplicates (1)") || !strings.Contains(output, "Release.al:1") {
+		t.Errorf("console output should list the duplicate ID, got %q", output)
+	}
+	if strings.Contains(ToConsole(createTestSummary()), "Duplicates") {
+		t.Error("console output should not contain a duplicates section without duplicates")
+	}
+
+	filePath := filepath.Join(t.TempDir(), "duplicates.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Duplicates", "F3"); value != "Release Sales" {
+		t.Errorf("expected Release Sales in F3, got %q", value)
+	}
+
+	if err := ToPDF(summary, filepath.Join(t.TempDir(), "duplicates.pdf")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportPropertyBreakdownSheetName(t *testing.T) {
+	summary := counter.CountObjects([]scanner.BCObject{
+		{Type: "page", ID: "50100", Name: "Setup", Properties: scanner.Properties{"Ünit/Größe:Übersicht*Äöü Äöü Äöü": "Ja"}},
+	})
+	summary.GroupBy("Ünit/Größe:Übersicht*Äöü Äöü Äöü")
+
+	filePath := filepath.Join(t.TempDir(), "properties.xlsx")
+	if err := ToExcel(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sheet := "By Ünit_Größe_Übersicht_Äöü Äöü"
+	if value, _ := f.GetCellValue(sheet, "A2"); value != "Ja" {
+		t.Errorf("expected Ja in sheet %q, got %q (sheets %v)", sheet, value, f.GetSheetList())
+	}
+}
+
+func TestUniqueSheetName(t *testing.T) {
+	used := map[string]bool{"summary": true}
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Summary", "Summary (2)"},
+		{"App A/B", "App A_B"},
+		{"app a/b", "app a_b (2)"},
+		{"App Contoso Sales and Receivables Extension", "App Contoso Sales and Receivabl"},
+	}
+	for _, tt := range tests {
+		if got := uniqueSheetName(tt.name, used); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestCheckExports(t *testing.T) {
+	app := scanner.AppManifest{ID: "1", Name: "Sales", IDRanges: []scanner.IDRange{{From: 50100, To: 50149}}, Dir: t.TempDir()}
+	report, err := check.Run(&scanner.Result{
+		Findings: scanner.Findings{Objects: []scanner.BCObject{
+			{Type: "table", ID: "50100", Name: "Sales Setup", FilePath: "Setup.al", Line: 1, Column: 1, App: app.Info()},
+			{Type: "codeunit", ID: "60000", Name: "Out Of Range", FilePath: "Logic.al", Line: 3, Column: 1, App: app.Info()},
+		}},
+		Apps: []scanner.AppManifest{app},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := CheckToConsole(report)
+	if !strings.Contains(output, `Logic.al:3:1: id-range: codeunit 60000 "Out Of Range" is outside the app's ID ranges (50100..50149)`) {
+		t.Errorf("console output should list the finding, got %q", output)
+	}
+	if !strings.Contains(output, "id-range : 1") || !strings.Contains(output, "TOTAL    : 1") {
+		t.Errorf("console output should count findings by rule, got %q", output)
+	}
+
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "check.json")
+	if err := CheckToJSON(report, jsonFile); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"rule": "id-range"`) {
+		t.Error("JSON should contain the finding's rule")
+	}
+
+	for _, name := range []string{"check.xlsx", "check.pdf"} {
+		path := filepath.Join(tmpDir, name)
+		var err error
+		if strings.HasSuffix(name, ".xlsx") {
+			err = CheckToExcel(report, path)
+		} else {
+			err = CheckToPDF(report, path)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s was not written", name)
+		}
+	}
+}
+
+func TestIDsExports(t *testing.T) {
+	app := scanner.AppInfo{ID: "1", Name: "Sales"}
+	result := &ids.Result{
+		App:       app,
+		Type:      ids.TypeField,
+		Ranges:    []scanner.IDRange{{From: 50100, To: 50149}},
+		Extension: `tableextension 50100 "Customer Ext"`,
+		Table:     "Customer",
+		IDs:       []int{50101, 50102},
+	}
+
+	output := IDsToConsole("Next Free IDs", result)
+	for _, want := range []string{"Next Free IDs", "Extension : tableextension 50100", "50100..50149", "  50102\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("console output should contain %q, got %q", want, output)
+		}
+	}
+
+	jsonStr, err := IDsToJSONString(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ids.Result
+	if err := json.Unmarshal([]byte(jsonStr), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Table != "Customer" || len(decoded.IDs) != 2 {
+		t.Errorf("unexpected JSON result %+v", decoded)
+	}
+
+	ledger := &ids.Ledger{Reservations: []ids.Reservation{{Type: "codeunit", ID: 50100, Name: "Post", By: "alice", Date: "2026-10-16"}}}
+	output = ReservationsToConsole(&app, ledger)
+	if !strings.Contains(output, `codeunit 50100 "Post" by alice on 2026-10-16`) || !strings.Contains(output, "TOTAL : 1") {
+		t.Errorf("unexpected reservations output %q", output)
+	}
+}
+
+func TestConflictExports(t *testing.T) {
+	app := scanner.AppManifest{ID: "1", Name: "Sales"}
+	report := &conflict.Report{
+		Repository: ".",
+		MergeBase:  "986fef3a6c0f4b3e2f1d0c9b8a7f6e5d4c3b2a19",
+		Ours:       conflict.Side{Ref: "feature", Commit: "02b1cb4e6c0f4b3e2f1d0c9b8a7f6e5d4c3b2a19", Added: 1},
+		Theirs:     conflict.Side{Ref: "main", Commit: "c0dfa30e6c0f4b3e2f1d0c9b8a7f6e5d4c3b2a19", Added: 1},
+		Collisions: conflict.Compare(
+			[]scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Release", FilePath: "src/Release.al", Line: 1, App: app.Info()}},
+			[]scanner.BCObject{{Type: "codeunit", ID: "50101", Name: "Archive", FilePath: "src/Archive.al", Line: 1, App: app.Info()}},
+		),
+	}
+	report.TotalCollisions = len(report.Collisions)
+
+	output := ConflictsToConsole(report)
+	for _, want := range []string{"Ours       : feature (02b1cb4), 1 object(s) added", "Merge base : 986fef3", "codeunit ID 50101 in Sales", "theirs  src/Archive.al:1  codeunit 50101 Archive", "TOTAL : 1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("console output should contain %q, got %q", want, output)
+		}
+	}
+
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "conflicts.json")
+	if err := ConflictsToJSON(report, jsonFile); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"totalCollisions": 1`) {
+		t.Error("JSON should contain the collision count")
+	}
+
+	xlsxFile := filepath.Join(tmpDir, "conflicts.xlsx")
+	if err := ConflictsToExcel(report, xlsxFile); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(xlsxFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Collisions", "G3"); value != "Archive" {
+		t.Errorf("expected the theirs object on the third row, got %q", value)
+	}
+
+	pdfFile := filepath.Join(tmpDir, "conflicts.pdf")
+	if err := ConflictsToPDF(report, pdfFile); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(pdfFile); err != nil || info.Size() == 0 {
+		t.Error("PDF was not written")
+	}
+}
+
+func TestDiffExports(t *testing.T) {
+	report := diff.Compare(
+		diff.Snapshot{Name: "v1", Root: "/v1", Objects: []scanner.BCObject{
+			{Type: "codeunit", ID: "50100", Name: "Post", FilePath: "/v1/Post.al"},
+			{Type: "table", ID: "50100", Name: "Setup", FilePath: "/v1/Setup.al"},
+		}},
+		diff.Snapshot{Name: "v2", Root: "/v2", Objects: []scanner.BCObject{
+			{Type: "codeunit", ID: "50100", Name: "Post Sales", FilePath: "/v2/Post.al"},
+			{Type: "page", ID: "50100", Name: "Setup | Card", FilePath: "/v2/Setup.al"},
+		}},
+	)
+
+	output := DiffToConsole(report)
+	for _, want := range []string{`codeunit 50100 "Post" → "Post Sales"`, "Added (1)", "page                   : 0 → 1 (+1)", "TOTAL      : 3"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("console output should contain %q, got %q", want, output)
+		}
+	}
+
+	markdown := DiffToMarkdown(report)
+	for _, want := range []string{"## Renamed", "| page | 0 | 1 | +1 |", `| page |  | 50100 |  | Setup \| Card |  | Setup.al |`} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown should contain %q, got %q", want, markdown)
+		}
+	}
+
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "diff.json")
+	if err := DiffToJSON(report, jsonFile); err != nil {
+		t.Fatal(err)
+	}
+	var decoded diff.Report
+	content, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TotalChanges != 3 || decoded.Changes[0].Kind != diff.KindAdded {
+		t.Errorf("unexpected JSON report %+v", decoded)
+	}
+
+	xlsxFile := filepath.Join(tmpDir, "diff.xlsx")
+	if err := DiffToExcel(report, xlsxFile); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(xlsxFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if value, _ := f.GetCellValue("Changes", "F4"); value != "Post Sales" {
+		t.Errorf("expected the renamed object on the fourth row, got %q", value)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	summary := createTestSummary()
+	summary.Root = "/src/app"
+	filePath := filepath.Join(t.TempDir(), "summary.json")
+	if err := ToJSON(summary, filePath); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "{\n  \"schemaVersion\": 1,") {
+		t.Errorf("JSON should start with the schema version, got %q", content[:40])
+	}
+
+	loaded, err := FromJSON(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.TotalObjects != 3 || loaded.Root != "/src/app" || len(loaded.CountsByType) != 3 {
+		t.Errorf("unexpected summary %+v", loaded)
+	}
+	if objs := loaded.ObjectsByType["table"]; len(objs) != 1 || objs[0].Name != "Test Table" {
+		t.Errorf("expected ObjectsByType to be rebuilt, got %+v", loaded.ObjectsByType)
+	}
+	if ToConsole(loaded) != ToConsole(summary) {
+		t.Error("console output of the loaded summary should match the original")
+	}
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"before schema versions", `{"totalObjects": 1, "objects": [{"type": "page", "id": "21", "name": "Customer Card"}]}`, ""},
+		{"newer schema", `{"schemaVersion": 99, "objects": []}`, "schema version 99 is newer"},
+		{"other report", `{"totalFindings": 0, "findings": []}`, "not a JSON summary"},
+		{"not JSON", `# Snapshot Diff`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := FromJSONBytes([]byte(tt.json))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(loaded.ObjectsByType["page"]) != 1 {
+					t.Errorf("expected ObjectsByType to be rebuilt, got %+v", loaded.ObjectsByType)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/ids"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// IDsToConsole formats free or reserved IDs for console output under title.
+func IDsToConsole(title string, result *ids.Result) string {
+	var sb strings.Builder
+
+	ranges := make([]string, len(result.Ranges))
+	for i, r := range result.Ranges {
+		ranges[i] = r.String()
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString("═══════════════════════════════════════════\n")
+	sb.WriteString(fmt.Sprintf("       %s\n", title))
+	sb.WriteString("═══════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("  App       : %s\n", result.App.Label()))
+	sb.WriteString(fmt.Sprintf("  Type      : %s\n", result.Type))
+	if result.Extension != "" {
+		sb.WriteString(fmt.Sprintf("  Extension : %s\n", result.Extension))
+	}
+	sb.WriteString(fmt.Sprintf("  Ranges    : %s\n", strings.Join(ranges, ", ")))
+	sb.WriteString("\n───────────────────────────────────────────\n")
+	for _, id := range result.IDs {
+		sb.WriteString(fmt.Sprintf("  %d\n", id))
+	}
+	sb.WriteString("═══════════════════════════════════════════\n")
+
+	return sb.String()
+}
+
+// IDsToJSONString returns free IDs as a JSON string.
+func IDsToJSONString(result *ids.Result) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ReservationsToConsole formats the reservations of an app's ledger for
+// console output.
+func ReservationsToConsole(app *scanner.AppInfo, ledger *ids.Ledger) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString("═══════════════════════════════════════════\n")
+	sb.WriteString("       Reserved IDs\n")
+	sb.WriteString("═══════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("  App : %s\n", app.Label()))
+
+	if len(ledger.Reservations) > 0 {
+		sb.WriteString("\n───────────────────────────────────────────\n")
+	}
+	for _, r := range ledger.Reservations {
+		line := fmt.Sprintf("  %s", r)
+		if r.By != "" {
+			line += fmt.Sprintf(" by %s", r.By)
+		}
+		if r.Date != "" {
+			line += fmt.Sprintf(" on %s", r.Date)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("───────────────────────────────────────────\n")
+	sb.WriteString(fmt.Sprintf("  TOTAL : %d\n", len(ledger.Reservations)))
+	sb.WriteString("═══════════════════════════════════════════\n")
+
+	return sb.String()
+}
@@ -3,14 +3,30 @@ package export
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/andrijan/bc-objects-counter/internal/counter"
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
 )
 
+// SchemaVersion is the version of the JSON summary format written by ToJSON.
+// Bump it when a change to counter.Summary or scanner.BCObject would make
+// older readers misinterpret the file; new optional fields do not need a bump.
+// Files without a schemaVersion were written before it was introduced and
+// are read as version 0.
+const SchemaVersion = 1
+
+// jsonSummary is the JSON document of a summary: its fields, preceded by the
+// schema version.
+type jsonSummary struct {
+	SchemaVersion int `json:"schemaVersion"`
+	*counter.Summary
+}
+
 // ToJSON exports the summary to a JSON file.
 func ToJSON(summary *counter.Summary, filePath string) error {
-	data, err := json.MarshalIndent(summary, "", "  ")
+	data, err := json.MarshalIndent(jsonSummary{SchemaVersion, summary}, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -20,9 +36,51 @@ func ToJSON(summary *counter.Summary, filePath string) error {
 
 // ToJSONString returns the summary as a JSON string.
 func ToJSONString(summary *counter.Summary) (string, error) {
-	data, err := json.MarshalIndent(summary, "", "  ")
+	data, err := json.MarshalIndent(jsonSummary{SchemaVersion, summary}, "", "  ")
 	if err != nil {
 		return "", err
 	}
 	return string(data), nil
 }
+
+// FromJSON reads a summary written by ToJSON.
+func FromJSON(filePath string) (*counter.Summary, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := FromJSONBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return summary, nil
+}
+
+// FromJSONBytes parses a summary written by ToJSON, of the current or an
+// earlier schema version. ObjectsByType is rebuilt from Objects.
+func FromJSONBytes(data []byte) (*counter.Summary, error) {
+	// Reports of other commands are JSON too; a summary always lists its objects
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, ok := fields["objects"]; !ok {
+		return nil, fmt.Errorf("not a JSON summary: no objects")
+	}
+
+	doc := jsonSummary{Summary: &counter.Summary{}}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON summary: %w", err)
+	}
+	if doc.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("schema version %d is newer than the supported version %d", doc.SchemaVersion, SchemaVersion)
+	}
+
+	summary := doc.Summary
+	summary.ObjectsByType = make(map[string][]scanner.BCObject)
+	for _, obj := range summary.Objects {
+		summary.ObjectsByType[obj.Type] = append(summary.ObjectsByType[obj.Type], obj)
+	}
+	return summary, nil
+}
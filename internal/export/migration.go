@@ -0,0 +1,230 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/migrate"
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// counterpartNames lists the AL objects matched to a C/AL object, e.g.
+// "tableextension 50100 Customer Ext".
+func counterpartNames(obj migrate.ObjectStatus) string {
+	names := make([]string, 0, len(obj.Counterparts))
+	for _, c := range obj.Counterparts {
+		names = append(names, strings.TrimSpace(fmt.Sprintf("%s %s %s", c.Type, c.ID, c.Name)))
+	}
+	return strings.Join(names, ", ")
+}
+
+// MigrationToConsole formats a migration report for console output.
+func MigrationToConsole(report *migrate.Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString("═══════════════════════════════════════════\n")
+	sb.WriteString("       C/AL Migration Status\n")
+	sb.WriteString("═══════════════════════════════════════════\n\n")
+
+	for _, c := range report.CountsByStatus {
+		padding := strings.Repeat(" ", 9-len(c.Status))
+		sb.WriteString(fmt.Sprintf("  %s%s : %d\n", c.Status, padding, c.Count))
+	}
+
+	sb.WriteString("\n───────────────────────────────────────────\n")
+	sb.WriteString(fmt.Sprintf("  TOTAL     : %d\n", report.TotalObjects))
+	sb.WriteString("═══════════════════════════════════════════\n")
+
+	// List what is left to do
+	if missing := report.GetCountByStatus(migrate.StatusMissing); missing > 0 {
+		sb.WriteString("\n  Missing\n")
+		sb.WriteString("───────────────────────────────────────────\n")
+		for _, obj := range report.Objects {
+			if obj.Status == migrate.StatusMissing {
+				sb.WriteString(fmt.Sprintf("  %-10s %-8s %s\n", obj.Type, obj.ID, obj.Name))
+			}
+		}
+		sb.WriteString("═══════════════════════════════════════════\n")
+	}
+
+	return sb.String()
+}
+
+// MigrationToJSON exports a migration report to a JSON file.
+func MigrationToJSON(report *migrate.Report, filePath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// MigrationToExcel exports a migration report to an Excel file.
+func MigrationToExcel(report *migrate.Report, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// Create Summary sheet
+	summarySheet := "Summary"
+	f.SetSheetName("Sheet1", summarySheet)
+
+	f.SetCellValue(summarySheet, "A1", "C/AL Migration Status")
+	f.SetCellValue(summarySheet, "A3", "Status")
+	f.SetCellValue(summarySheet, "B3", "Count")
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "#FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#4472C4"}, Pattern: 1},
+	})
+	f.SetCellStyle(summarySheet, "A3", "B3", headerStyle)
+
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 14},
+	})
+	f.SetCellStyle(summarySheet, "A1", "A1", titleStyle)
+
+	row := 4
+	for _, c := range report.CountsByStatus {
+		f.SetCellValue(summarySheet, fmt.Sprintf("A%d", row), string(c.Status))
+		f.SetCellValue(summarySheet, fmt.Sprintf("B%d", row), c.Count)
+		row++
+	}
+
+	row++
+	f.SetCellValue(summarySheet, fmt.Sprintf("A%d", row), "TOTAL")
+	f.SetCellValue(summarySheet, fmt.Sprintf("B%d", row), report.TotalObjects)
+	totalStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+	f.SetCellStyle(summarySheet, fmt.Sprintf("A%d", row), fmt.Sprintf("B%d", row), totalStyle)
+
+	f.SetColWidth(summarySheet, "A", "A", 25)
+	f.SetColWidth(summarySheet, "B", "B", 12)
+
+	// Create Objects sheet
+	objectsSheet := "Objects"
+	f.NewSheet(objectsSheet)
+
+	f.SetCellValue(objectsSheet, "A1", "Type")
+	f.SetCellValue(objectsSheet, "B1", "ID")
+	f.SetCellValue(objectsSheet, "C1", "Name")
+	f.SetCellValue(objectsSheet, "D1", "Modified")
+	f.SetCellValue(objectsSheet, "E1", "Version List")
+	f.SetCellValue(objectsSheet, "F1", "Status")
+	f.SetCellValue(objectsSheet, "G1", "Matched By")
+	f.SetCellValue(objectsSheet, "H1", "AL Counterparts")
+	f.SetCellStyle(objectsSheet, "A1", "H1", headerStyle)
+
+	row = 2
+	for _, obj := range report.Objects {
+		f.SetCellValue(objectsSheet, fmt.Sprintf("A%d", row), obj.Type)
+		f.SetCellValue(objectsSheet, fmt.Sprintf("B%d", row), obj.ID)
+		f.SetCellValue(objectsSheet, fmt.Sprintf("C%d", row), obj.Name)
+		f.SetCellValue(objectsSheet, fmt.Sprintf("D%d", row), obj.Modified)
+		f.SetCellValue(objectsSheet, fmt.Sprintf("E%d", row), obj.VersionList)
+		f.SetCellValue(objectsSheet, fmt.Sprintf("F%d", row), string(obj.Status))
+		f.SetCellValue(objectsSheet, fmt.Sprintf("G%d", row), obj.MatchedBy)
+		f.SetCellValue(objectsSheet, fmt.Sprintf("H%d", row), counterpartNames(obj))
+		row++
+	}
+
+	f.SetColWidth(objectsSheet, "A", "A", 15)
+	f.SetColWidth(objectsSheet, "B", "B", 10)
+	f.SetColWidth(objectsSheet, "C", "C", 40)
+	f.SetColWidth(objectsSheet, "D", "D", 10)
+	f.SetColWidth(objectsSheet, "E", "E", 30)
+	f.SetColWidth(objectsSheet, "F", "G", 12)
+	f.SetColWidth(objectsSheet, "H", "H", 60)
+
+	return f.SaveAs(filePath)
+}
+
+// MigrationToPDF exports a migration report to a PDF file.
+func MigrationToPDF(report *migrate.Report, filePath string) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("C/AL Migration Status", false)
+	pdf.SetAuthor("BC Objects Counter", false)
+
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 18)
+	pdf.Cell(0, 12, "C/AL Migration Status")
+	pdf.Ln(16)
+
+	// Status table header
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetFillColor(68, 114, 196)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.CellFormat(80, 8, "Status", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(40, 8, "Count", "1", 1, "C", true, 0, "")
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetTextColor(0, 0, 0)
+	for i, c := range report.CountsByStatus {
+		fill := i%2 == 0
+		if fill {
+			pdf.SetFillColor(240, 240, 240)
+		}
+		pdf.CellFormat(80, 7, string(c.Status), "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(40, 7, fmt.Sprintf("%d", c.Count), "1", 1, "C", fill, 0, "")
+	}
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(200, 200, 200)
+	pdf.CellFormat(80, 8, "TOTAL", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(40, 8, fmt.Sprintf("%d", report.TotalObjects), "1", 1, "C", true, 0, "")
+
+	// Objects section (new page)
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 10, "Objects")
+	pdf.Ln(14)
+
+	writeHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(25, 7, "Type", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(15, 7, "ID", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(55, 7, "Name", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(20, 7, "Status", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(75, 7, "AL Counterparts", "1", 1, "L", true, 0, "")
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	writeHeader()
+
+	for i, obj := range report.Objects {
+		if pdf.GetY() > 270 {
+			pdf.AddPage()
+			writeHeader()
+		}
+
+		fill := i%2 == 0
+		if fill {
+			pdf.SetFillColor(245, 245, 245)
+		}
+
+		name := obj.Name
+		if len(name) > 30 {
+			name = name[:27] + "..."
+		}
+		counterparts := counterpartNames(obj)
+		if len(counterparts) > 45 {
+			counterparts = counterparts[:42] + "..."
+		}
+
+		pdf.CellFormat(25, 6, obj.Type, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(15, 6, obj.ID, "1", 0, "C", fill, 0, "")
+		pdf.CellFormat(55, 6, name, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(20, 6, string(obj.Status), "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(75, 6, counterparts, "1", 1, "L", fill, 0, "")
+	}
+
+	return pdf.OutputFileAndClose(filePath)
+}
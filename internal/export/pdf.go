@@ -2,6 +2,7 @@ package export
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/andrijan/bc-objects-counter/internal/counter"
 	"github.com/go-pdf/fpdf"
@@ -46,6 +47,148 @@ func ToPDF(summary *counter.Summary, filePath string) error {
 	pdf.CellFormat(80, 8, "TOTAL", "1", 0, "L", true, 0, "")
 	pdf.CellFormat(40, 8, fmt.Sprintf("%d", summary.TotalObjects), "1", 1, "C", true, 0, "")
 
+	// Namespace breakdown
+	if summary.HasNamespaces() {
+		pdf.Ln(10)
+		pdf.SetFont("Arial", "B", 11)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(120, 8, "Namespace", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(40, 8, "Count", "1", 1, "C", true, 0, "")
+
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(0, 0, 0)
+		for i, c := range summary.CountsByNamespace {
+			if pdf.GetY() > 275 {
+				pdf.AddPage()
+			}
+			fill := i%2 == 0
+			if fill {
+				pdf.SetFillColor(240, 240, 240)
+			}
+			label := namespaceLabel(c.Namespace)
+			if len(label) > 60 {
+				label = label[:57] + "..."
+			}
+			pdf.CellFormat(120, 7, label, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(40, 7, fmt.Sprintf("%d", c.Count), "1", 1, "C", fill, 0, "")
+		}
+	}
+
+	// Lines of code per object type
+	if summary.Lines.Physical > 0 {
+		pdf.Ln(10)
+		pdf.SetFont("Arial", "B", 11)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(60, 8, "Object Type", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(30, 8, "Physical", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Code", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Comment", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Blank", "1", 1, "C", true, 0, "")
+
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(0, 0, 0)
+		for i, tl := range summary.LinesByType {
+			if pdf.GetY() > 275 {
+				pdf.AddPage()
+			}
+			fill := i%2 == 0
+			if fill {
+				pdf.SetFillColor(240, 240, 240)
+			}
+			pdf.CellFormat(60, 7, tl.Type, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(30, 7, fmt.Sprintf("%d", tl.Lines.Physical), "1", 0, "C", fill, 0, "")
+			pdf.CellFormat(30, 7, fmt.Sprintf("%d", tl.Lines.Code), "1", 0, "C", fill, 0, "")
+			pdf.CellFormat(30, 7, fmt.Sprintf("%d", tl.Lines.Comment), "1", 0, "C", fill, 0, "")
+			pdf.CellFormat(30, 7, fmt.Sprintf("%d", tl.Lines.Blank), "1", 1, "C", fill, 0, "")
+		}
+
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetFillColor(200, 200, 200)
+		pdf.CellFormat(60, 8, "TOTAL", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(30, 8, fmt.Sprintf("%d", summary.Lines.Physical), "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, fmt.Sprintf("%d", summary.Lines.Code), "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, fmt.Sprintf("%d", summary.Lines.Comment), "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, fmt.Sprintf("%d", summary.Lines.Blank), "1", 1, "C", true, 0, "")
+	}
+
+	// Property breakdown (--group-by)
+	if summary.ByProperty != nil {
+		pdf.Ln(10)
+		pdf.SetFont("Arial", "B", 11)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(120, 8, summary.ByProperty.Property, "1", 0, "L", true, 0, "")
+		pdf.CellFormat(40, 8, "Count", "1", 1, "C", true, 0, "")
+
+		pdf.SetFont("Arial", "", 10)
+		pdf.SetTextColor(0, 0, 0)
+		for i, c := range summary.ByProperty.Counts {
+			if pdf.GetY() > 275 {
+				pdf.AddPage()
+			}
+			fill := i%2 == 0
+			if fill {
+				pdf.SetFillColor(240, 240, 240)
+			}
+			label := propertyLabel(c.Value)
+			if len(label) > 60 {
+				label = label[:57] + "..."
+			}
+			pdf.CellFormat(120, 7, label, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(40, 7, fmt.Sprintf("%d", c.Count), "1", 1, "C", fill, 0, "")
+		}
+	}
+
+	// Apps section (new page), one table per app
+	if len(summary.Apps) > 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, fmt.Sprintf("Objects by App (%d)", len(summary.Apps)))
+		pdf.Ln(14)
+
+		for _, app := range summary.Apps {
+			if pdf.GetY() > 240 {
+				pdf.AddPage()
+			}
+			label := app.App.Label()
+			if len(label) > 80 {
+				label = label[:77] + "..."
+			}
+			pdf.SetFont("Arial", "B", 11)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.Cell(0, 8, label)
+			pdf.Ln(9)
+
+			pdf.SetFont("Arial", "B", 10)
+			pdf.SetFillColor(68, 114, 196)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.CellFormat(80, 7, "Object Type", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(40, 7, "Count", "1", 1, "C", true, 0, "")
+
+			pdf.SetFont("Arial", "", 10)
+			pdf.SetTextColor(0, 0, 0)
+			for i, c := range app.CountsByType {
+				if pdf.GetY() > 275 {
+					pdf.AddPage()
+				}
+				fill := i%2 == 0
+				if fill {
+					pdf.SetFillColor(240, 240, 240)
+				}
+				pdf.CellFormat(80, 7, c.Type, "1", 0, "L", fill, 0, "")
+				pdf.CellFormat(40, 7, fmt.Sprintf("%d", c.Count), "1", 1, "C", fill, 0, "")
+			}
+
+			pdf.SetFont("Arial", "B", 10)
+			pdf.SetFillColor(200, 200, 200)
+			pdf.CellFormat(80, 7, "TOTAL", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(40, 7, fmt.Sprintf("%d", app.TotalObjects), "1", 1, "C", true, 0, "")
+			pdf.Ln(8)
+		}
+	}
+
 	// Details section (new page)
 	pdf.AddPage()
 	pdf.SetFont("Arial", "B", 14)
@@ -53,31 +196,27 @@ func ToPDF(summary *counter.Summary, filePath string) error {
 	pdf.Ln(14)
 
 	// Details table header
-	pdf.SetFont("Arial", "B", 9)
-	pdf.SetFillColor(68, 114, 196)
-	pdf.SetTextColor(255, 255, 255)
-	pdf.CellFormat(35, 7, "Type", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(20, 7, "ID", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(70, 7, "Name", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(65, 7, "File", "1", 1, "L", true, 0, "")
+	writeDetailsHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(68, 114, 196)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.CellFormat(30, 7, "Type", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(15, 7, "ID", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(55, 7, "Name", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(45, 7, "Extends / Implements", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(45, 7, "File", "1", 1, "L", true, 0, "")
+		pdf.SetFont("Arial", "", 8)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	writeDetailsHeader()
 
 	// Details table data
-	pdf.SetFont("Arial", "", 8)
-	pdf.SetTextColor(0, 0, 0)
 	for i, obj := range summary.Objects {
 		// Check if we need a new page
 		if pdf.GetY() > 270 {
 			pdf.AddPage()
 			// Repeat header on new page
-			pdf.SetFont("Arial", "B", 9)
-			pdf.SetFillColor(68, 114, 196)
-			pdf.SetTextColor(255, 255, 255)
-			pdf.CellFormat(35, 7, "Type", "1", 0, "L", true, 0, "")
-			pdf.CellFormat(20, 7, "ID", "1", 0, "C", true, 0, "")
-			pdf.CellFormat(70, 7, "Name", "1", 0, "L", true, 0, "")
-			pdf.CellFormat(65, 7, "File", "1", 1, "L", true, 0, "")
-			pdf.SetFont("Arial", "", 8)
-			pdf.SetTextColor(0, 0, 0)
+			writeDetailsHeader()
 		}
 
 		fill := i%2 == 0
@@ -85,22 +224,294 @@ func ToPDF(summary *counter.Summary, filePath string) error {
 			pdf.SetFillColor(245, 245, 245)
 		}
 
-		// Truncate file path if too long
+		// Truncate file path if too long, keeping the line suffix visible
 		filePath := obj.FilePath
-		if len(filePath) > 40 {
-			filePath = "..." + filePath[len(filePath)-37:]
+		if obj.Line > 0 {
+			filePath = fmt.Sprintf("%s:%d", filePath, obj.Line)
+		}
+		if len(filePath) > 28 {
+			filePath = "..." + filePath[len(filePath)-25:]
 		}
 
 		// Truncate name if too long
 		name := obj.Name
-		if len(name) > 35 {
-			name = name[:32] + "..."
+		if len(name) > 30 {
+			name = name[:27] + "..."
+		}
+
+		// Extension objects show their target, others the interfaces they implement
+		relation := obj.Extends
+		if relation == "" {
+			relation = strings.Join(obj.Implements, ", ")
+		}
+		if len(relation) > 26 {
+			relation = relation[:23] + "..."
+		}
+
+		pdf.CellFormat(30, 6, obj.Type, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(15, 6, obj.ID, "1", 0, "C", fill, 0, "")
+		pdf.CellFormat(55, 6, name, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(45, 6, relation, "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(45, 6, filePath, "1", 1, "L", fill, 0, "")
+	}
+
+	// Diagnostics section (new page, only when there is something to report)
+	if len(summary.Diagnostics) > 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, "Scan Diagnostics")
+		pdf.Ln(14)
+
+		writeDiagnosticsHeader := func() {
+			pdf.SetFont("Arial", "B", 9)
+			pdf.SetFillColor(68, 114, 196)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.CellFormat(20, 7, "Severity", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(25, 7, "Code", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(65, 7, "Location", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(80, 7, "Message", "1", 1, "L", true, 0, "")
+			pdf.SetFont("Arial", "", 8)
+			pdf.SetTextColor(0, 0, 0)
+		}
+		writeDiagnosticsHeader()
+
+		for i, d := range summary.Diagnostics {
+			if pdf.GetY() > 270 {
+				pdf.AddPage()
+				writeDiagnosticsHeader()
+			}
+
+			fill := i%2 == 0
+			if fill {
+				pdf.SetFillColor(245, 245, 245)
+			}
+
+			location := d.File
+			if d.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, d.Line)
+			}
+			if len(location) > 40 {
+				location = "..." + location[len(location)-37:]
+			}
+			message := d.Message
+			if len(message) > 50 {
+				message = message[:47] + "..."
+			}
+
+			pdf.CellFormat(20, 6, string(d.Severity), "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(25, 6, d.Code, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(65, 6, location, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(80, 6, message, "1", 1, "L", fill, 0, "")
 		}
+	}
+
+	// Duplicate IDs and names (new page)
+	if len(summary.Duplicates) > 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, fmt.Sprintf("Duplicate IDs and Names (%d)", len(summary.Duplicates)))
+		pdf.Ln(14)
 
-		pdf.CellFormat(35, 6, obj.Type, "1", 0, "L", fill, 0, "")
-		pdf.CellFormat(20, 6, obj.ID, "1", 0, "C", fill, 0, "")
-		pdf.CellFormat(70, 6, name, "1", 0, "L", fill, 0, "")
-		pdf.CellFormat(65, 6, filePath, "1", 1, "L", fill, 0, "")
+		writeDuplicatesHeader := func() {
+			pdf.SetFont("Arial", "B", 9)
+			pdf.SetFillColor(68, 114, 196)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.CellFormat(60, 7, "Duplicate", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(65, 7, "Object", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(65, 7, "File", "1", 1, "L", true, 0, "")
+			pdf.SetFont("Arial", "", 8)
+			pdf.SetTextColor(0, 0, 0)
+		}
+		writeDuplicatesHeader()
+
+		row := 0
+		for _, d := range summary.Duplicates {
+			label := fmt.Sprintf("%s %s %s", d.Type, duplicateKindLabel(d.Kind), d.Value)
+			if len(label) > 36 {
+				label = label[:33] + "..."
+			}
+			for _, obj := range d.Objects {
+				if pdf.GetY() > 270 {
+					pdf.AddPage()
+					writeDuplicatesHeader()
+				}
+
+				fill := row%2 == 0
+				if fill {
+					pdf.SetFillColor(245, 245, 245)
+				}
+				row++
+
+				object := strings.TrimSpace(fmt.Sprintf("%s %s %s", obj.Type, obj.ID, obj.Name))
+				if len(object) > 40 {
+					object = object[:37] + "..."
+				}
+				location := fmt.Sprintf("%s:%d", obj.FilePath, obj.Line)
+				if len(location) > 40 {
+					location = "..." + location[len(location)-37:]
+				}
+
+				pdf.CellFormat(60, 6, label, "1", 0, "L", fill, 0, "")
+				pdf.CellFormat(65, 6, object, "1", 0, "L", fill, 0, "")
+				pdf.CellFormat(65, 6, location, "1", 1, "L", fill, 0, "")
+			}
+		}
+	}
+
+	// Most complex routines (new page)
+	if len(summary.MostComplex) > 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, fmt.Sprintf("Most Complex Routines (top %d)", len(summary.MostComplex)))
+		pdf.Ln(14)
+
+		writeComplexityHeader := func() {
+			pdf.SetFont("Arial", "B", 9)
+			pdf.SetFillColor(68, 114, 196)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.CellFormat(22, 7, "Complexity", "1", 0, "C", true, 0, "")
+			pdf.CellFormat(18, 7, "Nesting", "1", 0, "C", true, 0, "")
+			pdf.CellFormat(70, 7, "Object", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(80, 7, "Routine", "1", 1, "L", true, 0, "")
+			pdf.SetFont("Arial", "", 8)
+			pdf.SetTextColor(0, 0, 0)
+		}
+		writeComplexityHeader()
+
+		for i, r := range summary.MostComplex {
+			if pdf.GetY() > 270 {
+				pdf.AddPage()
+				writeComplexityHeader()
+			}
+
+			fill := i%2 == 0
+			if fill {
+				pdf.SetFillColor(245, 245, 245)
+			}
+
+			object := strings.TrimSpace(fmt.Sprintf("%s %s %s", r.ObjectType, r.ObjectID, r.ObjectName))
+			if len(object) > 40 {
+				object = object[:37] + "..."
+			}
+			routine := fmt.Sprintf("%s %s", r.Kind, r.Name)
+			if len(routine) > 45 {
+				routine = routine[:42] + "..."
+			}
+
+			pdf.CellFormat(22, 6, fmt.Sprintf("%d", r.Complexity), "1", 0, "C", fill, 0, "")
+			pdf.CellFormat(18, 6, fmt.Sprintf("%d", r.Nesting), "1", 0, "C", fill, 0, "")
+			pdf.CellFormat(70, 6, object, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(80, 6, routine, "1", 1, "L", fill, 0, "")
+		}
+	}
+
+	// Obsolete section (new page), grouped by ObsoleteTag
+	if len(summary.Obsolete) > 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, fmt.Sprintf("Obsolete Objects and Members (%d)", summary.CountObsolete()))
+		pdf.Ln(14)
+
+		writeObsoleteHeader := func() {
+			pdf.SetFont("Arial", "B", 9)
+			pdf.SetFillColor(68, 114, 196)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.CellFormat(20, 7, "State", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(55, 7, "Object", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(50, 7, "Item", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(65, 7, "Reason", "1", 1, "L", true, 0, "")
+			pdf.SetFont("Arial", "", 8)
+			pdf.SetTextColor(0, 0, 0)
+		}
+
+		for _, g := range summary.Obsolete {
+			if pdf.GetY() > 260 {
+				pdf.AddPage()
+			}
+			pdf.SetFont("Arial", "B", 11)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.Cell(0, 8, fmt.Sprintf("%s: %d (%d pending, %d removed)", obsoleteTagLabel(g.Tag), g.Count, g.Pending, g.Removed))
+			pdf.Ln(9)
+			writeObsoleteHeader()
+
+			for i, e := range g.Entries {
+				if pdf.GetY() > 270 {
+					pdf.AddPage()
+					writeObsoleteHeader()
+				}
+
+				fill := i%2 == 0
+				if fill {
+					pdf.SetFillColor(245, 245, 245)
+				}
+
+				object := strings.TrimSpace(fmt.Sprintf("%s %s %s", e.ObjectType, e.ObjectID, e.ObjectName))
+				if len(object) > 32 {
+					object = object[:29] + "..."
+				}
+				item := obsoleteItemLabel(e)
+				if len(item) > 30 {
+					item = item[:27] + "..."
+				}
+				reason := e.Reason
+				if len(reason) > 40 {
+					reason = reason[:37] + "..."
+				}
+
+				pdf.CellFormat(20, 6, e.State, "1", 0, "L", fill, 0, "")
+				pdf.CellFormat(55, 6, object, "1", 0, "L", fill, 0, "")
+				pdf.CellFormat(50, 6, item, "1", 0, "L", fill, 0, "")
+				pdf.CellFormat(65, 6, reason, "1", 1, "L", fill, 0, "")
+			}
+			pdf.Ln(4)
+		}
+	}
+
+	// Inactive objects section (new page, only with --show-inactive)
+	if len(summary.InactiveObjects) > 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, "Inactive Objects (not counted)")
+		pdf.Ln(14)
+
+		writeInactiveHeader := func() {
+			pdf.SetFont("Arial", "B", 9)
+			pdf.SetFillColor(68, 114, 196)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.CellFormat(30, 7, "Type", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(15, 7, "ID", "1", 0, "C", true, 0, "")
+			pdf.CellFormat(70, 7, "Name", "1", 0, "L", true, 0, "")
+			pdf.CellFormat(75, 7, "File", "1", 1, "L", true, 0, "")
+			pdf.SetFont("Arial", "", 8)
+			pdf.SetTextColor(0, 0, 0)
+		}
+		writeInactiveHeader()
+
+		for i, obj := range summary.InactiveObjects {
+			if pdf.GetY() > 270 {
+				pdf.AddPage()
+				writeInactiveHeader()
+			}
+
+			fill := i%2 == 0
+			if fill {
+				pdf.SetFillColor(245, 245, 245)
+			}
+
+			name := obj.Name
+			if len(name) > 40 {
+				name = name[:37] + "..."
+			}
+			location := fmt.Sprintf("%s:%d", obj.FilePath, obj.Line)
+			if len(location) > 45 {
+				location = "..." + location[len(location)-42:]
+			}
+
+			pdf.CellFormat(30, 6, obj.Type, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(15, 6, obj.ID, "1", 0, "C", fill, 0, "")
+			pdf.CellFormat(70, 6, name, "1", 0, "L", fill, 0, "")
+			pdf.CellFormat(75, 6, location, "1", 1, "L", fill, 0, "")
+		}
 	}
 
 	return pdf.OutputFileAndClose(filePath)
@@ -0,0 +1,303 @@
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+)
+
+// Commit is the part of a commit object needed to walk history.
+type Commit struct {
+	Hash    Hash
+	Tree    Hash
+	Parents []Hash
+}
+
+// File is a blob in a tree, with its slash-separated path from the root.
+type File struct {
+	Path string
+	Hash Hash
+	Data []byte
+}
+
+// ReadObject returns the type and contents of an object.
+func (r *Repository) ReadObject(h Hash) (string, []byte, error) {
+	return r.readObject(h, 0)
+}
+
+// readObject looks h up among the loose objects, then in the packs.
+func (r *Repository) readObject(h Hash, depth int) (string, []byte, error) {
+	objType, data, err := r.readLoose(h)
+	if err == nil {
+		return objType, data, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", nil, fmt.Errorf("object %s: %w", h, err)
+	}
+
+	for _, p := range r.packs {
+		if offset, ok := p.index.find(h); ok {
+			objType, data, err := p.read(r, offset, depth)
+			if err != nil {
+				return "", nil, fmt.Errorf("object %s: %w", h, err)
+			}
+			return objType, data, nil
+		}
+	}
+	return "", nil, fmt.Errorf("object %s not found", h)
+}
+
+// readLoose reads a zlib-compressed loose object: "<type> <size>\0<data>".
+func (r *Repository) readLoose(h Hash) (string, []byte, error) {
+	name := h.String()
+	f, err := os.Open(filepath.Join(r.commonDir, "objects", name[:2], name[2:]))
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	zr, err := zlib.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return "", nil, err
+	}
+	defer zr.Close()
+
+	br := bufio.NewReader(zr)
+	hdr, err := br.ReadString(0)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid loose object header")
+	}
+	objType, sizeText, ok := bytes.Cut([]byte(hdr[:len(hdr)-1]), []byte(" "))
+	if !ok {
+		return "", nil, fmt.Errorf("invalid loose object header")
+	}
+	size, err := strconv.ParseInt(string(sizeText), 10, 64)
+	if err != nil || size < 0 {
+		return "", nil, fmt.Errorf("invalid loose object size")
+	}
+
+	data, err := readObjectData(br, size)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(objType), data, nil
+}
+
+// readTyped reads an object and checks its type.
+func (r *Repository) readTyped(h Hash, want string) ([]byte, error) {
+	objType, data, err := r.ReadObject(h)
+	if err != nil {
+		return nil, err
+	}
+	if objType != want {
+		return nil, fmt.Errorf("object %s is a %s, not a %s", h.Short(), objType, want)
+	}
+	return data, nil
+}
+
+// Commit reads a commit object.
+func (r *Repository) Commit(h Hash) (*Commit, error) {
+	data, err := r.readTyped(h, TypeCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Commit{Hash: h}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			break
+		}
+		key, value, _ := bytes.Cut(line, []byte(" "))
+		switch string(key) {
+		case "tree":
+			if c.Tree, err = ParseHash(string(value)); err != nil {
+				return nil, fmt.Errorf("commit %s: %w", h.Short(), err)
+			}
+		case "parent":
+			parent, err := ParseHash(string(value))
+			if err != nil {
+				return nil, fmt.Errorf("commit %s: %w", h.Short(), err)
+			}
+			c.Parents = append(c.Parents, parent)
+		}
+	}
+	return c, nil
+}
+
+// treeEntry is an entry of a tree object.
+type treeEntry struct {
+	mode string
+	name string
+	hash Hash
+}
+
+// Tree file modes.
+const (
+	modeTree      = "40000"
+	modeSymlink   = "120000"
+	modeSubmodule = "160000"
+)
+
+// readTree parses a tree object: entries of "<mode> <name>\0<20-byte hash>".
+func (r *Repository) readTree(h Hash) ([]treeEntry, error) {
+	data, err := r.readTyped(h, TypeTree)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []treeEntry
+	for len(data) > 0 {
+		sp := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if sp < 0 || nul < sp || nul+21 > len(data) {
+			return nil, fmt.Errorf("tree %s is corrupt", h.Short())
+		}
+		e := treeEntry{mode: string(data[:sp]), name: string(data[sp+1 : nul])}
+		copy(e.hash[:], data[nul+1:nul+21])
+		entries = append(entries, e)
+		data = data[nul+21:]
+	}
+	return entries, nil
+}
+
+// Files returns the blobs in the tree of commit whose paths match, in tree
+// order. Symbolic links and submodules are skipped.
+func (r *Repository) Files(commit Hash, match func(path string) bool) ([]File, error) {
+	c, err := r.Commit(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	var walk func(tree Hash, dir string) error
+	walk = func(tree Hash, dir string) error {
+		entries, err := r.readTree(tree)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			p := path.Join(dir, e.name)
+			switch e.mode {
+			case modeTree:
+				if err := walk(e.hash, p); err != nil {
+					return err
+				}
+			case modeSymlink, modeSubmodule:
+			default:
+				if !match(p) {
+					continue
+				}
+				data, err := r.readTyped(e.hash, TypeBlob)
+				if err != nil {
+					return err
+				}
+				files = append(files, File{Path: p, Hash: e.hash, Data: data})
+			}
+		}
+		return nil
+	}
+
+	if err := walk(c.Tree, ""); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// MergeBase returns the best common ancestor of a and b: a common ancestor
+// that is not an ancestor of another common ancestor. When there are several
+// (criss-cross merges), the first found is returned. ok is false when the
+// commits share no history.
+func (r *Repository) MergeBase(a, b Hash) (base Hash, ok bool, err error) {
+	parents := make(map[Hash][]Hash)
+	parentsOf := func(h Hash) ([]Hash, error) {
+		if p, ok := parents[h]; ok {
+			return p, nil
+		}
+		c, err := r.Commit(h)
+		if err != nil {
+			return nil, err
+		}
+		parents[h] = c.Parents
+		return c.Parents, nil
+	}
+
+	// Everything reachable from a
+	fromA := map[Hash]bool{a: true}
+	queue := []Hash{a}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		ps, err := parentsOf(h)
+		if err != nil {
+			return Hash{}, false, err
+		}
+		for _, p := range ps {
+			if !fromA[p] {
+				fromA[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	// Walk back from b, stopping at the first commits also reachable from a
+	var candidates []Hash
+	seen := map[Hash]bool{b: true}
+	queue = []Hash{b}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if fromA[h] {
+			candidates = append(candidates, h)
+			continue
+		}
+		ps, err := parentsOf(h)
+		if err != nil {
+			return Hash{}, false, err
+		}
+		for _, p := range ps {
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Hash{}, false, nil
+	}
+
+	// Drop candidates that are ancestors of other candidates
+	redundant := make(map[Hash]bool)
+	for _, c := range candidates {
+		if redundant[c] {
+			continue
+		}
+		// Ancestors already marked have had their own ancestors marked too
+		queue = append([]Hash(nil), parents[c]...)
+		for len(queue) > 0 {
+			h := queue[0]
+			queue = queue[1:]
+			if redundant[h] {
+				continue
+			}
+			redundant[h] = true
+			ps, err := parentsOf(h)
+			if err != nil {
+				return Hash{}, false, err
+			}
+			queue = append(queue, ps...)
+		}
+	}
+	for _, c := range candidates {
+		if !redundant[c] {
+			return c, true, nil
+		}
+	}
+	return candidates[0], true, nil
+}
@@ -0,0 +1,400 @@
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Packed object types, as stored in pack entry headers.
+const (
+	packCommit   = 1
+	packTree     = 2
+	packBlob     = 3
+	packTag      = 4
+	packOfsDelta = 6
+	packRefDelta = 7
+)
+
+// packTypes maps packed object types to their names.
+var packTypes = map[int]string{
+	packCommit: TypeCommit,
+	packTree:   TypeTree,
+	packBlob:   TypeBlob,
+	packTag:    TypeTag,
+}
+
+// maxDeltaDepth bounds delta chains, guarding against corrupt packs.
+const maxDeltaDepth = 1000
+
+// maxPrealloc bounds the memory allocated for an object before its data has
+// been read, since object headers state sizes that may be corrupt.
+const maxPrealloc = 1 << 20
+
+// errCorruptObject reports object data that does not match its header.
+var errCorruptObject = errors.New("corrupt object")
+
+// maxCachedBases is the number of delta bases kept per pack, since objects
+// close together in a pack tend to share them.
+const maxCachedBases = 256
+
+// packIndex is a version 2 pack index (.idx) held in memory.
+type packIndex struct {
+	fanout  [256]uint32
+	names   []byte // count * 20 bytes, sorted
+	offsets []byte // count * 4 bytes
+	large   []byte // 8 bytes per offset with the high bit set above
+}
+
+// pack is a packfile and its index.
+type pack struct {
+	file  *os.File
+	index *packIndex
+
+	mu    sync.Mutex
+	bases map[int64]cachedObject
+}
+
+// cachedObject is a resolved object kept for use as a delta base.
+type cachedObject struct {
+	objType string
+	data    []byte
+}
+
+// openPacks opens every packfile with an index in the objects/pack directory.
+func (r *Repository) openPacks() error {
+	idxFiles, err := filepath.Glob(filepath.Join(r.commonDir, "objects", "pack", "*.idx"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(idxFiles)
+
+	for _, idxPath := range idxFiles {
+		index, err := readPackIndex(idxPath)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(strings.TrimSuffix(idxPath, ".idx") + ".pack")
+		if err != nil {
+			return err
+		}
+		r.packs = append(r.packs, &pack{file: file, index: index, bases: make(map[int64]cachedObject)})
+	}
+	return nil
+}
+
+// close closes the packfile.
+func (p *pack) close() error {
+	return p.file.Close()
+}
+
+// readPackIndex reads a version 2 pack index.
+func readPackIndex(path string) (*packIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	const headerSize = 8 + 256*4
+	if len(data) < headerSize || !bytes.Equal(data[:4], []byte{0xff, 't', 'O', 'c'}) {
+		return nil, fmt.Errorf("%s: unsupported pack index version 1", path)
+	}
+	if version := binary.BigEndian.Uint32(data[4:8]); version != 2 {
+		return nil, fmt.Errorf("%s: unsupported pack index version %d", path, version)
+	}
+
+	index := &packIndex{}
+	for i := range index.fanout {
+		index.fanout[i] = binary.BigEndian.Uint32(data[8+4*i:])
+	}
+	count := int(index.fanout[255])
+
+	namesStart := headerSize
+	offsetsStart := namesStart + count*20 + count*4 // skip the CRC32 table
+	largeStart := offsetsStart + count*4
+	if len(data) < largeStart+40 { // two trailing checksums
+		return nil, fmt.Errorf("%s: truncated pack index", path)
+	}
+	index.names = data[namesStart : namesStart+count*20]
+	index.offsets = data[offsetsStart:largeStart]
+	index.large = data[largeStart : len(data)-40]
+	return index, nil
+}
+
+// find returns the pack offset of h.
+func (idx *packIndex) find(h Hash) (int64, bool) {
+	lo := 0
+	if h[0] > 0 {
+		lo = int(idx.fanout[h[0]-1])
+	}
+	hi := int(idx.fanout[h[0]])
+
+	i := lo + sort.Search(hi-lo, func(i int) bool {
+		return bytes.Compare(idx.names[(lo+i)*20:(lo+i+1)*20], h[:]) >= 0
+	})
+	if i >= hi || !bytes.Equal(idx.names[i*20:(i+1)*20], h[:]) {
+		return 0, false
+	}
+
+	offset := binary.BigEndian.Uint32(idx.offsets[i*4:])
+	if offset&0x80000000 == 0 {
+		return int64(offset), true
+	}
+	pos := int(offset&0x7fffffff) * 8
+	if pos+8 > len(idx.large) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(idx.large[pos:])), true
+}
+
+// withPrefix returns the object names starting with the hexadecimal prefix.
+func (idx *packIndex) withPrefix(prefix string) []Hash {
+	var result []Hash
+	count := len(idx.names) / 20
+	for i := 0; i < count; i++ {
+		name := hex.EncodeToString(idx.names[i*20 : (i+1)*20])
+		if strings.HasPrefix(name, prefix) {
+			var h Hash
+			copy(h[:], idx.names[i*20:(i+1)*20])
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// read returns the object stored at offset, applying deltas. Reference
+// deltas are resolved through r, since their base may live anywhere.
+func (p *pack) read(r *Repository, offset int64, depth int) (string, []byte, error) {
+	if depth > maxDeltaDepth {
+		return "", nil, errors.New("delta chain too long")
+	}
+
+	p.mu.Lock()
+	cached, ok := p.bases[offset]
+	p.mu.Unlock()
+	if ok {
+		return cached.objType, cached.data, nil
+	}
+
+	br := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+	kind, size, err := readEntryHeader(br)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var objType string
+	var data []byte
+	switch kind {
+	case packCommit, packTree, packBlob, packTag:
+		objType = packTypes[kind]
+		if data, err = inflate(br, size); err != nil {
+			return "", nil, err
+		}
+
+	case packOfsDelta:
+		distance, err := readOffsetDistance(br)
+		if err != nil {
+			return "", nil, err
+		}
+		if distance <= 0 || distance > offset {
+			return "", nil, fmt.Errorf("invalid delta base offset at %d", offset)
+		}
+		delta, err := inflate(br, size)
+		if err != nil {
+			return "", nil, err
+		}
+		baseType, base, err := p.read(r, offset-distance, depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		objType = baseType
+		if data, err = applyDelta(base, delta); err != nil {
+			return "", nil, err
+		}
+
+	case packRefDelta:
+		var baseHash Hash
+		if _, err := io.ReadFull(br, baseHash[:]); err != nil {
+			return "", nil, err
+		}
+		delta, err := inflate(br, size)
+		if err != nil {
+			return "", nil, err
+		}
+		baseType, base, err := r.readObject(baseHash, depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		objType = baseType
+		if data, err = applyDelta(base, delta); err != nil {
+			return "", nil, err
+		}
+
+	default:
+		return "", nil, fmt.Errorf("unknown pack entry type %d at %d", kind, offset)
+	}
+
+	p.mu.Lock()
+	if len(p.bases) >= maxCachedBases {
+		p.bases = make(map[int64]cachedObject)
+	}
+	p.bases[offset] = cachedObject{objType: objType, data: data}
+	p.mu.Unlock()
+	return objType, data, nil
+}
+
+// readEntryHeader reads the type and inflated size of a pack entry.
+func readEntryHeader(br *bufio.Reader) (int, int64, error) {
+	b, err := br.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	kind := int(b>>4) & 7
+	size := int64(b & 0x0f)
+	for shift := 4; b&0x80 != 0; shift += 7 {
+		if b, err = br.ReadByte(); err != nil {
+			return 0, 0, err
+		}
+		size |= int64(b&0x7f) << shift
+	}
+	return kind, size, nil
+}
+
+// readOffsetDistance reads the base distance of an offset delta.
+func readOffsetDistance(br *bufio.Reader) (int64, error) {
+	b, err := br.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	distance := int64(b & 0x7f)
+	for b&0x80 != 0 {
+		if b, err = br.ReadByte(); err != nil {
+			return 0, err
+		}
+		distance = ((distance + 1) << 7) | int64(b&0x7f)
+	}
+	return distance, nil
+}
+
+// inflate decompresses a zlib stream of the given inflated size.
+func inflate(r io.Reader, size int64) ([]byte, error) {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	data, err := readObjectData(zr, size)
+	if err != nil {
+		return nil, fmt.Errorf("inflate: %w", err)
+	}
+	return data, nil
+}
+
+// readObjectData reads the data of an object declared to be size bytes long.
+// The declared size only bounds the buffer allocated up front, so a corrupt
+// size cannot force a huge allocation; data of another length is reported as
+// a corrupt object.
+func readObjectData(r io.Reader, size int64) ([]byte, error) {
+	if size < 0 {
+		return nil, errCorruptObject
+	}
+	var buf bytes.Buffer
+	buf.Grow(int(min(size, maxPrealloc)))
+	if _, err := io.Copy(&buf, io.LimitReader(r, size+1)); err != nil {
+		return nil, err
+	}
+	if int64(buf.Len()) != size {
+		return nil, errCorruptObject
+	}
+	return buf.Bytes(), nil
+}
+
+// applyDelta rebuilds an object from its base and a git delta.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	errCorrupt := errors.New("corrupt delta")
+
+	pos := 0
+	readSize := func() (int, bool) {
+		size, shift := 0, 0
+		for pos < len(delta) {
+			b := delta[pos]
+			pos++
+			size |= int(b&0x7f) << shift
+			shift += 7
+			if b&0x80 == 0 {
+				return size, true
+			}
+		}
+		return 0, false
+	}
+
+	baseSize, ok := readSize()
+	if !ok || baseSize != len(base) {
+		return nil, errCorrupt
+	}
+	resultSize, ok := readSize()
+	if !ok {
+		return nil, errCorrupt
+	}
+
+	result := make([]byte, 0, min(resultSize, maxPrealloc))
+	for pos < len(delta) {
+		op := delta[pos]
+		pos++
+
+		if op&0x80 == 0 {
+			// Insert the next op bytes
+			n := int(op)
+			if n == 0 || pos+n > len(delta) || len(result)+n > resultSize {
+				return nil, errCorrupt
+			}
+			result = append(result, delta[pos:pos+n]...)
+			pos += n
+			continue
+		}
+
+		// Copy from base; the low bits say which offset and size bytes follow
+		var offset, size int
+		for i := 0; i < 4; i++ {
+			if op&(1<<i) != 0 {
+				if pos >= len(delta) {
+					return nil, errCorrupt
+				}
+				offset |= int(delta[pos]) << (8 * i)
+				pos++
+			}
+		}
+		for i := 0; i < 3; i++ {
+			if op&(0x10<<i) != 0 {
+				if pos >= len(delta) {
+					return nil, errCorrupt
+				}
+				size |= int(delta[pos]) << (8 * i)
+				pos++
+			}
+		}
+		if size == 0 {
+			size = 0x10000
+		}
+		if offset+size > len(base) || len(result)+size > resultSize {
+			return nil, errCorrupt
+		}
+		result = append(result, base[offset:offset+size]...)
+	}
+
+	if len(result) != resultSize {
+		return nil, errCorrupt
+	}
+	return result, nil
+}
@@ -0,0 +1,433 @@
+// Package gitrepo reads commits, trees and blobs straight from a local git
+// repository's .git directory, without running git or touching the working
+// tree. It supports loose objects, packfiles (version 2 indexes, with offset
+// and reference deltas), loose and packed refs, and merge bases. Repositories
+// using SHA-256 object names are not supported.
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Hash is a SHA-1 object name.
+type Hash [20]byte
+
+// String returns the hash in hexadecimal.
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// Short returns the first 7 hexadecimal digits of the hash.
+func (h Hash) Short() string {
+	return h.String()[:7]
+}
+
+// ParseHash parses a full hexadecimal object name.
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	if len(s) != 2*len(h) {
+		return h, fmt.Errorf("invalid object name %q", s)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("invalid object name %q", s)
+	}
+	return h, nil
+}
+
+// Object types.
+const (
+	TypeCommit = "commit"
+	TypeTree   = "tree"
+	TypeBlob   = "blob"
+	TypeTag    = "tag"
+)
+
+// Repository is a git repository opened for reading.
+type Repository struct {
+	// gitDir holds the per-worktree files such as HEAD; commonDir holds the
+	// objects and refs shared by all worktrees. They are the same directory
+	// except in linked worktrees.
+	gitDir    string
+	commonDir string
+	packs     []*pack
+}
+
+// Open opens the repository at path: a working tree containing .git (a
+// directory, or a file pointing to one as in linked worktrees), or a bare
+// repository or .git directory itself.
+func Open(path string) (*Repository, error) {
+	gitDir, err := findGitDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		dir := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(gitDir, dir)
+		}
+		commonDir = filepath.Clean(dir)
+	}
+
+	if format := configValue(filepath.Join(commonDir, "config"), "extensions", "objectformat"); format != "" && !strings.EqualFold(format, "sha1") {
+		return nil, fmt.Errorf("%s: object format %s is not supported", commonDir, format)
+	}
+
+	repo := &Repository{gitDir: gitDir, commonDir: commonDir}
+	if err := repo.openPacks(); err != nil {
+		repo.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close releases the packfiles held open by the repository.
+func (r *Repository) Close() error {
+	var firstErr error
+	for _, p := range r.packs {
+		if err := p.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	r.packs = nil
+	return firstErr
+}
+
+// findGitDir returns the git directory of the repository at path.
+func findGitDir(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	dotGit := filepath.Join(abs, ".git")
+	info, err := os.Stat(dotGit)
+	switch {
+	case err == nil && info.IsDir():
+		return dotGit, nil
+	case err == nil:
+		// Linked worktrees and submodules: "gitdir: <path>"
+		data, err := os.ReadFile(dotGit)
+		if err != nil {
+			return "", err
+		}
+		line := strings.TrimSpace(string(data))
+		dir, ok := strings.CutPrefix(line, "gitdir:")
+		if !ok {
+			return "", fmt.Errorf("%s: not a gitdir file", dotGit)
+		}
+		dir = strings.TrimSpace(dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(abs, dir)
+		}
+		return filepath.Clean(dir), nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return "", err
+	}
+
+	// Bare repository or .git directory
+	if isGitDir(abs) {
+		return abs, nil
+	}
+	return "", fmt.Errorf("%s is not a git repository", abs)
+}
+
+// isGitDir reports whether dir looks like a git directory.
+func isGitDir(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// configValue returns the value of key in section of a git config file, or
+// "" if it is not set. Section and key names are case-insensitive.
+func configValue(path, section, key string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var current string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			current = strings.ToLower(strings.Trim(line, "[] \t"))
+			continue
+		}
+		if current != strings.ToLower(section) {
+			continue
+		}
+		name, value, _ := strings.Cut(line, "=")
+		if strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// Resolve returns the commit named by rev: a full or abbreviated object name,
+// HEAD, or a branch, tag or remote-tracking branch, optionally followed by
+// "^", "^N" and "~N" suffixes. Annotated tags are peeled to their commit.
+func (r *Repository) Resolve(rev string) (Hash, error) {
+	name, suffix := rev, ""
+	if i := strings.IndexAny(rev, "^~"); i >= 0 {
+		name, suffix = rev[:i], rev[i:]
+	}
+	if name == "" {
+		name = "HEAD"
+	}
+
+	h, err := r.resolveName(name)
+	if err != nil {
+		return Hash{}, err
+	}
+	if h, err = r.peel(h); err != nil {
+		return Hash{}, err
+	}
+
+	for suffix != "" {
+		op := suffix[0]
+		suffix = suffix[1:]
+		digits := len(suffix) - len(strings.TrimLeft(suffix, "0123456789"))
+		n := 1
+		if digits > 0 {
+			n, _ = strconv.Atoi(suffix[:digits])
+			suffix = suffix[digits:]
+		}
+
+		switch op {
+		case '^':
+			if n == 0 {
+				continue
+			}
+			c, err := r.Commit(h)
+			if err != nil {
+				return Hash{}, err
+			}
+			if n > len(c.Parents) {
+				return Hash{}, fmt.Errorf("%s: commit %s has no parent %d", rev, h.Short(), n)
+			}
+			h = c.Parents[n-1]
+		case '~':
+			for ; n > 0; n-- {
+				c, err := r.Commit(h)
+				if err != nil {
+					return Hash{}, err
+				}
+				if len(c.Parents) == 0 {
+					return Hash{}, fmt.Errorf("%s: commit %s has no parent", rev, h.Short())
+				}
+				h = c.Parents[0]
+			}
+		default:
+			return Hash{}, fmt.Errorf("unsupported revision %q", rev)
+		}
+	}
+	return h, nil
+}
+
+// resolveName resolves a ref name or object name, in the order git uses.
+func (r *Repository) resolveName(name string) (Hash, error) {
+	if h, err := ParseHash(name); err == nil {
+		return h, nil
+	}
+
+	var candidates []string
+	if strings.HasPrefix(name, "refs/") || isPseudoRef(name) {
+		candidates = append(candidates, name)
+	}
+	candidates = append(candidates, "refs/"+name, "refs/tags/"+name, "refs/heads/"+name, "refs/remotes/"+name, "refs/remotes/"+name+"/HEAD")
+	for _, ref := range candidates {
+		h, ok, err := r.readRef(ref, 0)
+		if err != nil {
+			return Hash{}, err
+		}
+		if ok {
+			return h, nil
+		}
+	}
+
+	if len(name) >= 4 && isHex(name) {
+		return r.findPrefix(strings.ToLower(name))
+	}
+	return Hash{}, fmt.Errorf("unknown revision %q", name)
+}
+
+// isPseudoRef reports whether name is a ref such as HEAD or ORIG_HEAD stored
+// directly in the git directory.
+func isPseudoRef(name string) bool {
+	for _, c := range name {
+		if (c < 'A' || c > 'Z') && c != '_' {
+			return false
+		}
+	}
+	return strings.HasSuffix(name, "HEAD")
+}
+
+// readRef reads a loose or packed ref, following symbolic refs.
+func (r *Repository) readRef(name string, depth int) (Hash, bool, error) {
+	if depth > 10 {
+		return Hash{}, false, fmt.Errorf("ref %s: too many levels of symbolic refs", name)
+	}
+
+	// HEAD and other pseudo refs live in the worktree's git dir
+	dir := r.commonDir
+	if !strings.HasPrefix(name, "refs/") {
+		dir = r.gitDir
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err == nil {
+		content := strings.TrimSpace(string(data))
+		if target, ok := strings.CutPrefix(content, "ref:"); ok {
+			return r.readRef(strings.TrimSpace(target), depth+1)
+		}
+		h, err := ParseHash(content)
+		if err != nil {
+			return Hash{}, false, fmt.Errorf("ref %s: %w", name, err)
+		}
+		return h, true, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) && !isDirError(err) {
+		return Hash{}, false, err
+	}
+
+	if !strings.HasPrefix(name, "refs/") {
+		return Hash{}, false, nil
+	}
+	return r.packedRef(name)
+}
+
+// isDirError reports whether err comes from reading a directory as a file,
+// as happens when a ref name is a prefix of other refs.
+func isDirError(err error) bool {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		return false
+	}
+	info, statErr := os.Stat(pathErr.Path)
+	return statErr == nil && info.IsDir()
+}
+
+// packedRef looks name up in the packed-refs file.
+func (r *Repository) packedRef(name string) (Hash, bool, error) {
+	data, err := os.ReadFile(filepath.Join(r.commonDir, "packed-refs"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Hash{}, false, nil
+	}
+	if err != nil {
+		return Hash{}, false, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		// Comments, and "^<hash>" lines peeling the tag above
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		hash, ref, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || ref != name {
+			continue
+		}
+		h, err := ParseHash(hash)
+		if err != nil {
+			return Hash{}, false, fmt.Errorf("packed-refs: %w", err)
+		}
+		return h, true, nil
+	}
+	return Hash{}, false, nil
+}
+
+// findPrefix returns the only object whose name starts with prefix.
+func (r *Repository) findPrefix(prefix string) (Hash, error) {
+	matches := make(map[Hash]bool)
+
+	entries, _ := os.ReadDir(filepath.Join(r.commonDir, "objects", prefix[:2]))
+	for _, e := range entries {
+		if name := prefix[:2] + e.Name(); strings.HasPrefix(name, prefix) {
+			if h, err := ParseHash(name); err == nil {
+				matches[h] = true
+			}
+		}
+	}
+	for _, p := range r.packs {
+		for _, h := range p.index.withPrefix(prefix) {
+			matches[h] = true
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Hash{}, fmt.Errorf("unknown revision %q", prefix)
+	case 1:
+		for h := range matches {
+			return h, nil
+		}
+	}
+	return Hash{}, fmt.Errorf("short object name %q is ambiguous", prefix)
+}
+
+// isHex reports whether s consists of hexadecimal digits only.
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// peel follows annotated tags to the object they point at, and checks that
+// the result is a commit.
+func (r *Repository) peel(h Hash) (Hash, error) {
+	for i := 0; i < 10; i++ {
+		objType, data, err := r.ReadObject(h)
+		if err != nil {
+			return Hash{}, err
+		}
+		switch objType {
+		case TypeCommit:
+			return h, nil
+		case TypeTag:
+			target, ok := header(data, "object")
+			if !ok {
+				return Hash{}, fmt.Errorf("tag %s has no object", h.Short())
+			}
+			next, err := ParseHash(target)
+			if err != nil {
+				return Hash{}, fmt.Errorf("tag %s: %w", h.Short(), err)
+			}
+			h = next
+		default:
+			return Hash{}, fmt.Errorf("%s is a %s, not a commit", h.Short(), objType)
+		}
+	}
+	return Hash{}, fmt.Errorf("too many nested tags at %s", h.Short())
+}
+
+// header returns the value of the first header line key in a commit or tag.
+func header(data []byte, key string) (string, bool) {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			// End of headers
+			break
+		}
+		if k, v, ok := bytes.Cut(line, []byte(" ")); ok && string(k) == key {
+			return string(v), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,270 @@
+package gitrepo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git runs the git command line tool in dir, which the tests compare against.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createRepo builds a repository with diverging branches main and feature,
+// an annotated tag on main, and large, similar files that git stores as
+// deltas once packed.
+func createRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+
+	var body strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&body, "    // line %d of a long procedure body\n", i)
+	}
+	writeFile(t, dir, "app.json", `{"id": "1", "name": "Sales"}`)
+	writeFile(t, dir, "src/Post.Codeunit.al", "codeunit 50100 \"Post\"\n{\n"+body.String()+"}\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "base")
+
+	git(t, dir, "checkout", "-q", "-b", "feature")
+	writeFile(t, dir, "src/Release.Codeunit.al", "codeunit 50101 \"Release\"\n{\n"+body.String()+"// feature\n}\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "feature")
+
+	git(t, dir, "checkout", "-q", "main")
+	writeFile(t, dir, "src/Post.Codeunit.al", "codeunit 50100 \"Post\"\n{\n"+body.String()+"// main\n}\n")
+	writeFile(t, dir, "src/Archive.Codeunit.al", "codeunit 50101 \"Archive\"\n{\n}\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "main")
+	git(t, dir, "tag", "-a", "v1", "-m", "release")
+	return dir
+}
+
+// checkObjects compares every object in the repository with git cat-file.
+func checkObjects(t *testing.T, dir string) {
+	t.Helper()
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	for _, line := range strings.Split(git(t, dir, "rev-list", "--objects", "--all"), "\n") {
+		name, _, _ := strings.Cut(line, " ")
+		h, err := ParseHash(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objType, data, err := repo.ReadObject(h)
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if want := git(t, dir, "cat-file", "-t", name); objType != want {
+			t.Errorf("%s: expected type %s, got %s", name, want, objType)
+		}
+		want, err := exec.Command("git", "-C", dir, "cat-file", objType, name).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("%s: contents differ from git cat-file", name)
+		}
+	}
+}
+
+func TestReadObjects(t *testing.T) {
+	dir := createRepo(t)
+
+	t.Run("loose", func(t *testing.T) {
+		checkObjects(t, dir)
+	})
+	t.Run("packed", func(t *testing.T) {
+		git(t, dir, "repack", "-q", "-a", "-d", "-f", "--depth=50", "--window=50")
+		git(t, dir, "pack-refs", "--all")
+		checkObjects(t, dir)
+	})
+	t.Run("ref deltas", func(t *testing.T) {
+		git(t, dir, "-c", "repack.useDeltaBaseOffset=false", "repack", "-q", "-a", "-d", "-f", "--depth=50", "--window=50")
+		checkObjects(t, dir)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	dir := createRepo(t)
+	git(t, dir, "pack-refs", "--all")
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	for _, rev := range []string{"HEAD", "main", "feature", "refs/heads/feature", "v1", "main~1", "HEAD^", "feature^0"} {
+		h, err := repo.Resolve(rev)
+		if err != nil {
+			t.Errorf("%s: %v", rev, err)
+			continue
+		}
+		if want := git(t, dir, "rev-parse", rev+"^{commit}"); h.String() != want {
+			t.Errorf("%s: expected %s, got %s", rev, want, h)
+		}
+	}
+
+	head := git(t, dir, "rev-parse", "HEAD")
+	if h, err := repo.Resolve(head[:8]); err != nil || h.String() != head {
+		t.Errorf("expected abbreviated name to resolve to %s, got %s, %v", head, h, err)
+	}
+	if _, err := repo.Resolve("missing"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+	if _, err := repo.Resolve("main~5"); err == nil {
+		t.Error("expected an error walking past the root commit")
+	}
+}
+
+func TestMergeBaseAndFiles(t *testing.T) {
+	dir := createRepo(t)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	main, _ := repo.Resolve("main")
+	feature, _ := repo.Resolve("feature")
+	base, ok, err := repo.MergeBase(main, feature)
+	if err != nil || !ok {
+		t.Fatalf("expected a merge base, got %v, %v", ok, err)
+	}
+	if want := git(t, dir, "merge-base", "main", "feature"); base.String() != want {
+		t.Errorf("expected merge base %s, got %s", want, base)
+	}
+
+	files, err := repo.Files(feature, func(path string) bool { return strings.HasSuffix(path, ".al") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	if strings.Join(paths, ",") != "src/Post.Codeunit.al,src/Release.Codeunit.al" {
+		t.Errorf("unexpected files %v", paths)
+	}
+	if !bytes.HasPrefix(files[1].Data, []byte(`codeunit 50101 "Release"`)) {
+		t.Errorf("unexpected contents %q", files[1].Data[:30])
+	}
+}
+
+func TestOpenWorktree(t *testing.T) {
+	dir := createRepo(t)
+	worktree := filepath.Join(t.TempDir(), "wt")
+	git(t, dir, "worktree", "add", "-q", worktree, "feature")
+
+	repo, err := Open(worktree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	h, err := repo.Resolve("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := git(t, dir, "rev-parse", "feature"); h.String() != want {
+		t.Errorf("expected the worktree HEAD %s, got %s", want, h)
+	}
+
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("expected an error opening a directory that is not a repository")
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("hello world")
+	// Base size 11, result size 12: copy "hello " (offset 0, size 6), insert "there!"
+	delta := []byte{11, 12, 0x90, 6, 6, 't', 'h', 'e', 'r', 'e', '!'}
+	got, err := applyDelta(base, delta)
+	if err != nil || string(got) != "hello there!" {
+		t.Errorf("expected %q, got %q, %v", "hello there!", got, err)
+	}
+	if _, err := applyDelta(base, []byte{10, 1, 1, 'x'}); err == nil {
+		t.Error("expected an error for a wrong base size")
+	}
+	// Result size 2^40, far more than the delta produces
+	if _, err := applyDelta(base, []byte{11, 0x80, 0x80, 0x80, 0x80, 0x80, 0x20, 0x90, 6}); err == nil {
+		t.Error("expected an error for a result size that does not match")
+	}
+	// Result size 3, but the copy produces 6 bytes
+	if _, err := applyDelta(base, []byte{11, 3, 0x90, 6}); err == nil {
+		t.Error("expected an error for a result larger than its declared size")
+	}
+}
+
+func TestReadCorruptLooseObject(t *testing.T) {
+	dir := createRepo(t)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	tests := map[string]string{
+		"huge size":  "blob 99999999999999\x00hello",
+		"short data": "blob 10\x00hello",
+		"long data":  "blob 2\x00hello",
+	}
+	i := 0
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			zw := zlib.NewWriter(&buf)
+			zw.Write([]byte(content))
+			zw.Close()
+
+			i++
+			h, err := ParseHash(fmt.Sprintf("%040x", 0xdead0000+i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			hex := h.String()
+			writeFile(t, filepath.Join(dir, ".git", "objects"), hex[:2]+"/"+hex[2:], buf.String())
+
+			if _, _, err := repo.ReadObject(h); err == nil || !strings.Contains(err.Error(), "corrupt object") {
+				t.Errorf("expected a corrupt object error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Package ids finds free object and field IDs within the ID ranges of an app.
+package ids
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// TypeField is the pseudo object type used to ask for free field IDs of a
+// tableextension.
+const TypeField = "field"
+
+// Query describes the IDs to look for.
+type Query struct {
+	// Type is an object type such as "codeunit", or TypeField.
+	Type string
+	// Count is the number of free IDs wanted.
+	Count int
+	// Extension is the name or ID of the tableextension whose free field IDs
+	// are wanted. Only used when Type is TypeField.
+	Extension string
+}
+
+// Result lists the free IDs found for a query.
+type Result struct {
+	App    scanner.AppInfo   `json:"app"`
+	Type   string            `json:"type"`
+	Ranges []scanner.IDRange `json:"ranges"`
+	// Extension is the tableextension of a field query, e.g.
+	// `tableextension 50100 "Customer Ext"`, and Table the table it extends.
+	Extension string `json:"extension,omitempty"`
+	Table     string `json:"table,omitempty"`
+	IDs       []int  `json:"ids"`
+}
+
+// SelectApp returns the app whose app.json lives in dir. With an empty dir,
+// the only app in apps is returned.
+func SelectApp(apps []scanner.AppManifest, dir string) (*scanner.AppManifest, error) {
+	if dir == "" {
+		switch len(apps) {
+		case 0:
+			return nil, fmt.Errorf("no app.json found")
+		case 1:
+			return &apps[0], nil
+		}
+		dirs := make([]string, len(apps))
+		for i := range apps {
+			dirs[i] = apps[i].Dir
+		}
+		return nil, fmt.Errorf("found %d apps, choose one with --app: %s", len(apps), strings.Join(dirs, ", "))
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid app folder: %w", err)
+	}
+	for i := range apps {
+		if filepath.Clean(apps[i].Dir) == absDir {
+			return &apps[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no app.json found in %s", absDir)
+}
+
+// Next returns the lowest q.Count IDs within the app's ID ranges that are not
+// used by its objects of type q.Type, or by the fields of the table extended by
+// the tableextension named in q.Extension, nor reserved in ledger (which may be
+// nil). It fails when the ranges do not hold enough free IDs.
+func Next(objects []scanner.BCObject, app *scanner.AppManifest, ledger *Ledger, q Query) (*Result, error) {
+	if q.Count < 1 {
+		return nil, fmt.Errorf("count must be at least 1, got %d", q.Count)
+	}
+	result, used, err := prepare(objects, app, q)
+	if err != nil {
+		return nil, err
+	}
+	reservedIDs(used, ledger, result.Type, result.Table)
+
+	result.IDs = freeIDs(result.Ranges, used, q.Count)
+	if len(result.IDs) < q.Count {
+		return nil, fmt.Errorf("only %d free %s ID(s) left in %s", len(result.IDs), q.Type, scanner.FormatRanges(result.Ranges))
+	}
+	return result, nil
+}
+
+// Take returns a result for the given IDs after checking that each lies
+// within the app's ID ranges and is not used by an object (or field) yet.
+// Reservations are not checked; Ledger.Reserve rejects reserved IDs.
+func Take(objects []scanner.BCObject, app *scanner.AppManifest, q Query, wanted []int) (*Result, error) {
+	result, used, err := prepare(objects, app, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range wanted {
+		if !inRanges(result.Ranges, id) {
+			return nil, fmt.Errorf("%s ID %d is outside the app's ID ranges (%s)", q.Type, id, scanner.FormatRanges(result.Ranges))
+		}
+		if used[id] {
+			return nil, fmt.Errorf("%s ID %d is already used", q.Type, id)
+		}
+	}
+	result.IDs = wanted
+	return result, nil
+}
+
+// prepare resolves a query against the app and returns the result to fill in
+// with the IDs already used.
+func prepare(objects []scanner.BCObject, app *scanner.AppManifest, q Query) (*Result, map[int]bool, error) {
+	ranges := app.Ranges()
+	if len(ranges) == 0 {
+		return nil, nil, fmt.Errorf("%s declares no idRanges", app.Path())
+	}
+
+	result := &Result{App: *app.Info(), Type: q.Type, Ranges: ranges}
+	appObjects := objectsOf(objects, result.App)
+
+	var used map[int]bool
+	switch {
+	case q.Type == TypeField:
+		ext, err := findExtension(appObjects, q.Extension)
+		if err != nil {
+			return nil, nil, err
+		}
+		result.Extension = fmt.Sprintf("%s %s \"%s\"", ext.Type, ext.ID, ext.Name)
+		result.Table = scanner.UnqualifiedName(ext.Extends)
+		used = usedFieldIDs(appObjects, ext)
+	case scanner.TypeHasID(q.Type):
+		used = make(map[int]bool)
+		for _, obj := range appObjects {
+			if obj.Type == q.Type {
+				markUsed(used, obj.ID)
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("object type %q has no IDs", q.Type)
+	}
+	return result, used, nil
+}
+
+// ExtendedTable returns the name, without namespace, of the table extended by
+// the tableextension named or numbered extension in app.
+func ExtendedTable(objects []scanner.BCObject, app *scanner.AppManifest, extension string) (string, error) {
+	ext, err := findExtension(objectsOf(objects, *app.Info()), extension)
+	if err != nil {
+		return "", err
+	}
+	return scanner.UnqualifiedName(ext.Extends), nil
+}
+
+// objectsOf returns the objects declared in the sources of app. Objects read
+// from .app packages or C/AL exports are left out.
+func objectsOf(objects []scanner.BCObject, app scanner.AppInfo) []scanner.BCObject {
+	var result []scanner.BCObject
+	for _, obj := range objects {
+		if obj.App == nil || *obj.App != app || !obj.FromSource() {
+			continue
+		}
+		result = append(result, obj)
+	}
+	return result
+}
+
+// findExtension returns the tableextension named or numbered ref.
+func findExtension(objects []scanner.BCObject, ref string) (*scanner.BCObject, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("field IDs need a tableextension name or ID")
+	}
+	for i := range objects {
+		obj := &objects[i]
+		if obj.Type == "tableextension" && (obj.ID == ref || strings.EqualFold(obj.Name, ref)) {
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("tableextension %q not found in the app", ref)
+}
+
+// usedFieldIDs returns the field IDs taken in the table extended by ext: the
+// fields of every tableextension of the app extending the same table, and of
+// the table itself when the app declares it.
+func usedFieldIDs(objects []scanner.BCObject, ext *scanner.BCObject) map[int]bool {
+	used := make(map[int]bool)
+	for _, obj := range objects {
+		table := scanner.UnqualifiedName(ext.Extends)
+		sameTable := (obj.Type == "tableextension" && strings.EqualFold(scanner.UnqualifiedName(obj.Extends), table)) ||
+			(obj.Type == "table" && strings.EqualFold(obj.Name, table))
+		if !sameTable {
+			continue
+		}
+		for _, m := range obj.Members {
+			if m.Kind == scanner.MemberField {
+				markUsed(used, m.ID)
+			}
+		}
+	}
+	return used
+}
+
+// markUsed records a numeric id as used. IDs that are not numbers are ignored.
+func markUsed(used map[int]bool, id string) {
+	if n, err := strconv.Atoi(id); err == nil {
+		used[n] = true
+	}
+}
+
+// freeIDs returns up to count IDs from ranges, in ascending order, that are
+// not used.
+func freeIDs(ranges []scanner.IDRange, used map[int]bool, count int) []int {
+	sorted := append([]scanner.IDRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+
+	var result []int
+	seen := make(map[int]bool)
+	for _, r := range sorted {
+		for id := r.From; id <= r.To && len(result) < count; id++ {
+			if !used[id] && !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// inRanges reports whether id lies in one of ranges.
+func inRanges(ranges []scanner.IDRange, id int) bool {
+	for _, r := range ranges {
+		if r.Contains(id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package ids
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+	"github.com/andrijan/bc-objects-counter/internal/testutil"
+)
+
+func scanApps(t *testing.T) (string, *scanner.Result) {
+	t.Helper()
+	root := t.TempDir()
+	testutil.WriteFiles(t, root, map[string]string{
+		"sales/app.json": `{"id": "1", "name": "Sales", "idRanges": [{"from": 70000, "to": 70002}, {"from": 50100, "to": 50104}]}`,
+		"sales/src/Objects.al": `codeunit 50100 "Post"
+{
+}
+
+codeunit 50102 "Release"
+{
+}
+
+table 50100 "Sales Setup"
+{
+    fields
+    {
+        field(1; "Primary Key"; Code[10]) { }
+    }
+}
+
+tableextension 50100 "Customer Ext" extends Customer
+{
+    fields
+    {
+        field(50100; "Sales Code"; Code[20]) { }
+        field(50101; "Sales Group"; Code[20]) { }
+    }
+}
+`,
+		"sales/src/More.al": `tableextension 50101 "Customer More" extends Microsoft.Sales.Customer.Customer
+{
+    fields
+    {
+        field(50103; "Region"; Code[10]) { }
+    }
+}
+`,
+		"other/app.json":     `{"id": "2", "name": "Other", "idRanges": [{"from": 50100, "to": 50199}]}`,
+		"other/src/Other.al": "codeunit 50101 \"Other\"\n{\n}\n",
+	})
+
+	result, err := scanner.ScanDirectory(root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, result
+}
+
+func TestNext(t *testing.T) {
+	root, result := scanApps(t)
+	app, err := SelectApp(result.Apps, filepath.Join(root, "sales"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query    Query
+		expected []int
+	}{
+		{Query{Type: "codeunit", Count: 1}, []int{50101}},
+		{Query{Type: "codeunit", Count: 4}, []int{50101, 50103, 50104, 70000}},
+		{Query{Type: "page", Count: 2}, []int{50100, 50101}},
+		{Query{Type: TypeField, Count: 3, Extension: "customer ext"}, []int{50102, 50104, 70000}},
+		{Query{Type: TypeField, Count: 1, Extension: "50101"}, []int{50102}},
+	}
+	for _, tt := range tests {
+		got, err := Next(result.Objects, app, nil, tt.query)
+		if err != nil {
+			t.Errorf("%+v: %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.IDs, tt.expected) {
+			t.Errorf("%+v: expected %v, got %v", tt.query, tt.expected, got.IDs)
+		}
+	}
+
+	got, _ := Next(result.Objects, app, nil, Query{Type: TypeField, Count: 1, Extension: "50101"})
+	if got.Extension != `tableextension 50101 "Customer More"` || got.Table != "Customer" || got.App.Name != "Sales" {
+		t.Errorf("unexpected result %+v", got)
+	}
+}
+
+func TestNextErrors(t *testing.T) {
+	root, result := scanApps(t)
+	app, err := SelectApp(result.Apps, filepath.Join(root, "sales"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query    Query
+		expected string
+	}{
+		{Query{Type: "codeunit", Count: 7}, "only 6 free codeunit ID(s) left in 70000..70002, 50100..50104"},
+		{Query{Type: "interface", Count: 1}, `object type "interface" has no IDs`},
+		{Query{Type: TypeField, Count: 1, Extension: "Vendor Ext"}, `tableextension "Vendor Ext" not found in the app`},
+		{Query{Type: TypeField, Count: 1}, "field IDs need a tableextension name or ID"},
+		{Query{Type: "codeunit", Count: 0}, "count must be at least 1, got 0"},
+	}
+	for _, tt := range tests {
+		_, err := Next(result.Objects, app, nil, tt.query)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("%+v: expected error %q, got %v", tt.query, tt.expected, err)
+		}
+	}
+}
+
+func TestSelectApp(t *testing.T) {
+	root, result := scanApps(t)
+
+	if _, err := SelectApp(result.Apps, ""); err == nil || !strings.HasPrefix(err.Error(), "found 2 apps") {
+		t.Errorf("expected an error asking to choose an app, got %v", err)
+	}
+	if _, err := SelectApp(result.Apps, filepath.Join(root, "missing")); err == nil {
+		t.Error("expected an error for a folder without app.json")
+	}
+	app, err := SelectApp(result.Apps[1:], "")
+	if err != nil || app.Name != "Sales" {
+		t.Errorf("expected the only app to be selected, got %+v, %v", app, err)
+	}
+}
@@ -0,0 +1,273 @@
+package ids
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// LedgerFileName is the name of the reservation ledger kept next to app.json.
+const LedgerFileName = ".objectids.json"
+
+// Lock timing for concurrent updates of a ledger.
+var (
+	lockTimeout = 5 * time.Second
+	lockRetry   = 50 * time.Millisecond
+)
+
+// Reservation is an object or field ID claimed before the object exists, so
+// that developers on other branches do not pick it too.
+type Reservation struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	// Table is the table extended by the tableextension a field ID is
+	// reserved for, without its namespace. Field IDs are unique per table,
+	// not per extension.
+	Table string `json:"table,omitempty"`
+	// Name is the intended name of the object or field.
+	Name string `json:"name,omitempty"`
+	By   string `json:"by,omitempty"`
+	Date string `json:"date,omitempty"`
+}
+
+// String formats the reservation as e.g. `codeunit 50100 "Post Sales"` or
+// `field 50101 "Sales Code" in table "Customer"`.
+func (r Reservation) String() string {
+	s := fmt.Sprintf("%s %d", r.Type, r.ID)
+	if r.Name != "" {
+		s += fmt.Sprintf(" %q", r.Name)
+	}
+	if r.Table != "" {
+		s += fmt.Sprintf(" in table %q", r.Table)
+	}
+	return s
+}
+
+// sameID reports whether r and other reserve the same ID.
+func (r Reservation) sameID(other Reservation) bool {
+	return r.Type == other.Type && r.ID == other.ID && strings.EqualFold(r.Table, other.Table)
+}
+
+// Ledger is the list of reservations of an app, stored as LedgerFileName in
+// the app's folder and committed with its sources.
+type Ledger struct {
+	Reservations []Reservation `json:"reservations"`
+}
+
+// LedgerPath returns the path of the ledger of app.
+func LedgerPath(app *scanner.AppManifest) string {
+	return filepath.Join(app.Dir, LedgerFileName)
+}
+
+// LoadLedger reads a ledger file. A missing file is an empty ledger.
+func LoadLedger(path string) (*Ledger, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Ledger{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ledger Ledger
+	if err := json.Unmarshal(data, &ledger); err != nil {
+		return nil, fmt.Errorf("invalid ledger %s: %w", path, err)
+	}
+	return &ledger, nil
+}
+
+// Find returns the reservations of type objType (and table, for fields).
+func (l *Ledger) Find(objType, table string) []Reservation {
+	var result []Reservation
+	for _, r := range l.Reservations {
+		if r.Type == objType && strings.EqualFold(r.Table, table) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Reserve adds r to the ledger unless its ID is already reserved.
+func (l *Ledger) Reserve(r Reservation) error {
+	for _, existing := range l.Reservations {
+		if existing.sameID(r) {
+			return fmt.Errorf("%s is already reserved", existing)
+		}
+	}
+	l.Reservations = append(l.Reservations, r)
+	return nil
+}
+
+// Release removes the reservations of id for objType (and table, for
+// fields) and returns them.
+func (l *Ledger) Release(objType, table string, id int) ([]Reservation, error) {
+	target := Reservation{Type: objType, ID: id, Table: table}
+	var kept, released []Reservation
+	for _, r := range l.Reservations {
+		if r.sameID(target) {
+			released = append(released, r)
+		} else {
+			kept = append(kept, r)
+		}
+	}
+	if len(released) == 0 {
+		return nil, fmt.Errorf("%s is not reserved", target)
+	}
+	l.Reservations = kept
+	return released, nil
+}
+
+// Duplicates returns the groups of reservations sharing an ID, as left behind
+// when branches reserving the same ID are merged.
+func (l *Ledger) Duplicates() [][]Reservation {
+	sorted := &Ledger{Reservations: append([]Reservation(nil), l.Reservations...)}
+	sorted.sort()
+
+	var groups [][]Reservation
+	reservations := sorted.Reservations
+	for i := 0; i < len(reservations); {
+		j := i + 1
+		for j < len(reservations) && reservations[j].sameID(reservations[i]) {
+			j++
+		}
+		if j-i > 1 {
+			groups = append(groups, reservations[i:j])
+		}
+		i = j
+	}
+	return groups
+}
+
+// sort orders reservations by type, table and ID.
+func (l *Ledger) sort() {
+	sort.SliceStable(l.Reservations, func(i, j int) bool {
+		a, b := l.Reservations[i], l.Reservations[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if !strings.EqualFold(a.Table, b.Table) {
+			return strings.ToLower(a.Table) < strings.ToLower(b.Table)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// Marshal returns the ledger as JSON with one reservation per line, sorted by
+// type, table and ID, so that reservations made on different branches merge
+// without conflicts.
+func (l *Ledger) Marshal() ([]byte, error) {
+	l.sort()
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n  \"reservations\": [")
+	for i, r := range l.Reservations {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n    ")
+		buf.Write(line)
+	}
+	if len(l.Reservations) > 0 {
+		buf.WriteString("\n  ")
+	}
+	buf.WriteString("]\n}\n")
+	return buf.Bytes(), nil
+}
+
+// Save writes the ledger to path, replacing the file atomically.
+func (l *Ledger) Save(path string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), LedgerFileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// UpdateLedger locks the ledger at path, applies update to its current
+// contents and saves the result. Concurrent updates from other processes wait
+// for the lock.
+func UpdateLedger(path string, update func(*Ledger) error) error {
+	unlock, err := lockLedger(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ledger, err := LoadLedger(path)
+	if err != nil {
+		return err
+	}
+	if err := update(ledger); err != nil {
+		return err
+	}
+	return ledger.Save(path)
+}
+
+// lockLedger takes the lock for the ledger at path by creating path.lock
+// exclusively, retrying until lockTimeout. The returned function releases it.
+func lockLedger(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("ledger is locked by another process; remove %s if it is stale", lockPath)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// reservedIDs marks the IDs reserved for objType (and table, for fields).
+func reservedIDs(used map[int]bool, ledger *Ledger, objType, table string) {
+	if ledger == nil {
+		return
+	}
+	for _, r := range ledger.Find(objType, table) {
+		used[r.ID] = true
+	}
+}
+
+// ParseID parses an object or field ID.
+func ParseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+	return id, nil
+}
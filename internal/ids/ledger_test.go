@@ -0,0 +1,177 @@
+package ids
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLedgerMarshal(t *testing.T) {
+	ledger := &Ledger{}
+	for _, r := range []Reservation{
+		{Type: "table", ID: 50110, Name: "Setup", By: "bob"},
+		{Type: "codeunit", ID: 50102, Name: "Release"},
+		{Type: TypeField, ID: 50105, Table: "Customer", Name: "Region"},
+		{Type: "codeunit", ID: 50101, Name: "Post", By: "alice", Date: "2026-10-16"},
+	} {
+		if err := ledger.Reserve(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := ledger.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "reservations": [
+    {"type":"codeunit","id":50101,"name":"Post","by":"alice","date":"2026-10-16"},
+    {"type":"codeunit","id":50102,"name":"Release"},
+    {"type":"field","id":50105,"table":"Customer","name":"Region"},
+    {"type":"table","id":50110,"name":"Setup","by":"bob"}
+  ]
+}
+`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	empty, _ := (&Ledger{}).Marshal()
+	if string(empty) != "{\n  \"reservations\": []\n}\n" {
+		t.Errorf("unexpected empty ledger %q", empty)
+	}
+}
+
+func TestLedgerReserveRelease(t *testing.T) {
+	ledger := &Ledger{}
+	if err := ledger.Reserve(Reservation{Type: TypeField, ID: 50100, Table: "Customer", Name: "Code"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.Reserve(Reservation{Type: TypeField, ID: 50100, Table: "customer"}); err == nil ||
+		err.Error() != `field 50100 "Code" in table "Customer" is already reserved` {
+		t.Errorf("expected the field to be reserved already, got %v", err)
+	}
+	if err := ledger.Reserve(Reservation{Type: TypeField, ID: 50100, Table: "Vendor"}); err != nil {
+		t.Errorf("field IDs of other tables should be independent: %v", err)
+	}
+
+	if _, err := ledger.Release("codeunit", "", 50100); err == nil || err.Error() != "codeunit 50100 is not reserved" {
+		t.Errorf("expected an error releasing an unreserved ID, got %v", err)
+	}
+	released, err := ledger.Release(TypeField, "CUSTOMER", 50100)
+	if err != nil || len(released) != 1 || released[0].Name != "Code" {
+		t.Errorf("unexpected release %+v, %v", released, err)
+	}
+	if len(ledger.Reservations) != 1 || ledger.Reservations[0].Table != "Vendor" {
+		t.Errorf("unexpected reservations left %+v", ledger.Reservations)
+	}
+}
+
+func TestLedgerDuplicates(t *testing.T) {
+	// As left behind by merging two branches that reserved the same ID
+	ledger := &Ledger{Reservations: []Reservation{
+		{Type: "codeunit", ID: 50101, Name: "Post", By: "alice"},
+		{Type: "codeunit", ID: 50102, Name: "Release"},
+		{Type: "codeunit", ID: 50101, Name: "Archive", By: "bob"},
+	}}
+	groups := ledger.Duplicates()
+	if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0].Name != "Post" || groups[0][1].Name != "Archive" {
+		t.Errorf("unexpected duplicates %+v", groups)
+	}
+	if ledger.Reservations[1].ID != 50102 {
+		t.Error("Duplicates should not reorder the ledger")
+	}
+}
+
+func TestUpdateLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LedgerFileName)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- UpdateLedger(path, func(l *Ledger) error {
+				return l.Reserve(Reservation{Type: "codeunit", ID: 50100 + len(l.Reservations)})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ledger, err := LoadLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Reservations) != 10 || len(ledger.Duplicates()) != 0 {
+		t.Errorf("expected 10 distinct reservations, got %+v", ledger.Reservations)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("expected the lock file to be removed")
+	}
+}
+
+func TestUpdateLedgerLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LedgerFileName)
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	defer func() { lockTimeout = timeout }()
+
+	err := UpdateLedger(path, func(*Ledger) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), "ledger is locked by another process") {
+		t.Errorf("expected a lock error, got %v", err)
+	}
+}
+
+func TestNextHonorsLedger(t *testing.T) {
+	root, result := scanApps(t)
+	app, err := SelectApp(result.Apps, filepath.Join(root, "sales"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ledger := &Ledger{Reservations: []Reservation{
+		{Type: "codeunit", ID: 50101},
+		{Type: TypeField, ID: 50102, Table: "customer"},
+		{Type: TypeField, ID: 50104, Table: "Vendor"},
+	}}
+	tests := []struct {
+		query    Query
+		expected []int
+	}{
+		{Query{Type: "codeunit", Count: 2}, []int{50103, 50104}},
+		{Query{Type: "page", Count: 1}, []int{50100}},
+		{Query{Type: TypeField, Count: 2, Extension: "Customer Ext"}, []int{50104, 70000}},
+	}
+	for _, tt := range tests {
+		got, err := Next(result.Objects, app, ledger, tt.query)
+		if err != nil {
+			t.Errorf("%+v: %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.IDs, tt.expected) {
+			t.Errorf("%+v: expected %v, got %v", tt.query, tt.expected, got.IDs)
+		}
+	}
+
+	if _, err := Take(result.Objects, app, Query{Type: "codeunit"}, []int{50100}); err == nil || err.Error() != "codeunit ID 50100 is already used" {
+		t.Errorf("expected an error taking a used ID, got %v", err)
+	}
+	if _, err := Take(result.Objects, app, Query{Type: "codeunit"}, []int{60000}); err == nil {
+		t.Error("expected an error taking an ID outside the ranges")
+	}
+}
@@ -0,0 +1,154 @@
+// Package migrate compares legacy C/AL objects against converted AL objects
+// to track the progress of a NAV to Business Central migration.
+package migrate
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+// Status is the migration state of a single C/AL object.
+type Status string
+
+const (
+	// StatusConverted means an AL object of the same type with the same ID or name exists.
+	StatusConverted Status = "converted"
+	// StatusPartial means the object is only covered by AL extension objects.
+	StatusPartial Status = "partial"
+	// StatusMissing means no AL counterpart was found.
+	StatusMissing Status = "missing"
+)
+
+// Match methods recorded on ObjectStatus.
+const (
+	MatchByID      = "id"
+	MatchByName    = "name"
+	MatchByExtends = "extends"
+)
+
+// extensionTypes maps C/AL object types to the AL extension type that can
+// carry their customizations.
+var extensionTypes = map[string]string{
+	"table":  "tableextension",
+	"page":   "pageextension",
+	"report": "reportextension",
+}
+
+// ObjectStatus is the migration status of one C/AL object and the AL objects it was matched to.
+type ObjectStatus struct {
+	Type         string             `json:"type"`
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Modified     bool               `json:"modified"`
+	VersionList  string             `json:"versionList,omitempty"`
+	FilePath     string             `json:"filePath"`
+	Status       Status             `json:"status"`
+	MatchedBy    string             `json:"matchedBy,omitempty"`
+	Counterparts []scanner.BCObject `json:"counterparts,omitempty"`
+}
+
+// StatusCount is the number of C/AL objects in a given status.
+type StatusCount struct {
+	Status Status `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// Report is the result of comparing a C/AL export against an AL source tree.
+type Report struct {
+	TotalObjects   int            `json:"totalObjects"`
+	CountsByStatus []StatusCount  `json:"countsByStatus"`
+	Objects        []ObjectStatus `json:"objects"`
+}
+
+// Compare matches every C/AL object against the AL objects. Objects of the
+// same type are matched by ID first, then by name; tables, pages and reports
+// without a direct counterpart are partially converted when AL extension
+// objects extend them. The result is sorted by type and numeric ID.
+func Compare(calObjects, alObjects []scanner.BCObject) *Report {
+	byID := make(map[string][]scanner.BCObject)
+	byName := make(map[string][]scanner.BCObject)
+	byTarget := make(map[string][]scanner.BCObject)
+	for _, obj := range alObjects {
+		byID[obj.Type+"|"+obj.ID] = append(byID[obj.Type+"|"+obj.ID], obj)
+		byName[obj.Type+"|"+strings.ToLower(obj.Name)] = append(byName[obj.Type+"|"+strings.ToLower(obj.Name)], obj)
+		if obj.Extends != "" {
+			key := obj.Type + "|" + strings.ToLower(scanner.UnqualifiedName(obj.Extends))
+			byTarget[key] = append(byTarget[key], obj)
+		}
+	}
+
+	report := &Report{TotalObjects: len(calObjects)}
+	counts := make(map[Status]int)
+
+	for _, cal := range calObjects {
+		status := ObjectStatus{
+			Type:     cal.Type,
+			ID:       cal.ID,
+			Name:     cal.Name,
+			FilePath: cal.FilePath,
+			Status:   StatusMissing,
+		}
+		if cal.CAL != nil {
+			status.Modified = cal.CAL.Modified
+			status.VersionList = cal.CAL.VersionList
+		}
+
+		switch {
+		case cal.ID != "" && len(byID[cal.Type+"|"+cal.ID]) > 0:
+			status.Status = StatusConverted
+			status.MatchedBy = MatchByID
+			status.Counterparts = byID[cal.Type+"|"+cal.ID]
+		case len(byName[cal.Type+"|"+strings.ToLower(cal.Name)]) > 0:
+			status.Status = StatusConverted
+			status.MatchedBy = MatchByName
+			status.Counterparts = byName[cal.Type+"|"+strings.ToLower(cal.Name)]
+		case extensionTypes[cal.Type] != "":
+			if exts := byTarget[extensionTypes[cal.Type]+"|"+strings.ToLower(cal.Name)]; len(exts) > 0 {
+				status.Status = StatusPartial
+				status.MatchedBy = MatchByExtends
+				status.Counterparts = exts
+			}
+		}
+
+		counts[status.Status]++
+		report.Objects = append(report.Objects, status)
+	}
+
+	for _, s := range []Status{StatusConverted, StatusPartial, StatusMissing} {
+		report.CountsByStatus = append(report.CountsByStatus, StatusCount{Status: s, Count: counts[s]})
+	}
+
+	sort.SliceStable(report.Objects, func(i, j int) bool {
+		a, b := report.Objects[i], report.Objects[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return scanner.NumericID(a.ID) < scanner.NumericID(b.ID)
+	})
+
+	return report
+}
+
+// FilterModified returns only the C/AL objects marked Modified=Yes, i.e. the
+// customized objects that actually need migrating.
+func FilterModified(objects []scanner.BCObject) []scanner.BCObject {
+	var result []scanner.BCObject
+	for _, obj := range objects {
+		if obj.CAL != nil && obj.CAL.Modified {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// GetCountByStatus returns the number of objects in the given status.
+func (r *Report) GetCountByStatus(status Status) int {
+	for _, c := range r.CountsByStatus {
+		if c.Status == status {
+			return c.Count
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/scanner"
+)
+
+func TestCompare(t *testing.T) {
+	calObjects := []scanner.BCObject{
+		{Type: "table", ID: "18", Name: "Customer", CAL: &scanner.CALProperties{Modified: true}},
+		{Type: "page", ID: "21", Name: "Customer Card", CAL: &scanner.CALProperties{Modified: true}},
+		{Type: "codeunit", ID: "50000", Name: "Sales Customizations", CAL: &scanner.CALProperties{Modified: true}},
+		{Type: "report", ID: "50001", Name: "Old Report", CAL: &scanner.CALProperties{Modified: true}},
+		{Type: "table", ID: "50002", Name: "Setup", CAL: &scanner.CALProperties{Modified: false}},
+	}
+	alObjects := []scanner.BCObject{
+		{Type: "tableextension", ID: "50100", Name: "Customer Ext", Extends: "Customer"},
+		{Type: "pageextension", ID: "50100", Name: "Customer Card Ext", Extends: `Microsoft.Sales.Customer."customer card"`},
+		{Type: "codeunit", ID: "50000", Name: "Sales Customizations"},
+		{Type: "table", ID: "50102", Name: "Setup"},
+	}
+
+	report := Compare(calObjects, alObjects)
+
+	expected := map[string]struct {
+		status    Status
+		matchedBy string
+	}{
+		"Customer":             {StatusPartial, MatchByExtends},
+		"Customer Card":        {StatusPartial, MatchByExtends},
+		"Sales Customizations": {StatusConverted, MatchByID},
+		"Old Report":           {StatusMissing, ""},
+		"Setup":                {StatusConverted, MatchByName},
+	}
+
+	if report.TotalObjects != 5 || len(report.Objects) != 5 {
+		t.Fatalf("expected 5 objects, got %d/%d", report.TotalObjects, len(report.Objects))
+	}
+	for _, obj := range report.Objects {
+		exp := expected[obj.Name]
+		if obj.Status != exp.status || obj.MatchedBy != exp.matchedBy {
+			t.Errorf("%s: expected %s by %q, got %s by %q", obj.Name, exp.status, exp.matchedBy, obj.Status, obj.MatchedBy)
+		}
+	}
+
+	if n := report.GetCountByStatus(StatusConverted); n != 2 {
+		t.Errorf("expected 2 converted, got %d", n)
+	}
+	if n := report.GetCountByStatus(StatusPartial); n != 2 {
+		t.Errorf("expected 2 partial, got %d", n)
+	}
+	if n := report.GetCountByStatus(StatusMissing); n != 1 {
+		t.Errorf("expected 1 missing, got %d", n)
+	}
+
+	// Sorted by type, then numeric ID
+	if report.Objects[0].Type != "codeunit" || report.Objects[len(report.Objects)-1].Name != "Setup" {
+		t.Errorf("unexpected order: first %s, last %s", report.Objects[0].Name, report.Objects[len(report.Objects)-1].Name)
+	}
+}
+
+func TestFilterModified(t *testing.T) {
+	objects := []scanner.BCObject{
+		{Type: "table", ID: "18", Name: "Customer", CAL: &scanner.CALProperties{Modified: true}},
+		{Type: "table", ID: "27", Name: "Item", CAL: &scanner.CALProperties{Modified: false}},
+		{Type: "table", ID: "50100", Name: "AL Table"},
+	}
+
+	modified := FilterModified(objects)
+	if len(modified) != 1 || modified[0].Name != "Customer" {
+		t.Errorf("expected only Customer, got %+v", modified)
+	}
+}
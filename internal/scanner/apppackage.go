@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// navxMagic is the signature at the start of a compiled .app package. The
+// NAVX header is followed by a regular zip archive.
+var navxMagic = []byte("NAVX")
+
+// symbolObject is an object entry in SymbolReference.json.
+type symbolObject struct {
+	ID                    int              `json:"Id"`
+	Name                  string           `json:"Name"`
+	TargetObject          string           `json:"TargetObject"`
+	ImplementedInterfaces []string         `json:"ImplementedInterfaces"`
+	Properties            []symbolProperty `json:"Properties"`
+}
+
+// symbolProperty is an object property in SymbolReference.json.
+type symbolProperty struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+// symbolApp is the app identity at the root of SymbolReference.json.
+type symbolApp struct {
+	AppID     string `json:"AppId"`
+	Name      string `json:"Name"`
+	Publisher string `json:"Publisher"`
+	Version   string `json:"Version"`
+}
+
+// symbolNamespace is a level of the namespace tree in SymbolReference.json.
+// The root of the file has the same shape, without a name.
+type symbolNamespace struct {
+	Name       string            `json:"Name"`
+	Namespaces []symbolNamespace `json:"Namespaces"`
+
+	Tables                  []symbolObject `json:"Tables"`
+	TableExtensions         []symbolObject `json:"TableExtensions"`
+	Pages                   []symbolObject `json:"Pages"`
+	PageExtensions          []symbolObject `json:"PageExtensions"`
+	Reports                 []symbolObject `json:"Reports"`
+	ReportExtensions        []symbolObject `json:"ReportExtensions"`
+	Codeunits               []symbolObject `json:"Codeunits"`
+	XmlPorts                []symbolObject `json:"XmlPorts"`
+	Queries                 []symbolObject `json:"Queries"`
+	EnumTypes               []symbolObject `json:"EnumTypes"`
+	EnumExtensionTypes      []symbolObject `json:"EnumExtensionTypes"`
+	Interfaces              []symbolObject `json:"Interfaces"`
+	PermissionSets          []symbolObject `json:"PermissionSets"`
+	PermissionSetExtensions []symbolObject `json:"PermissionSetExtensions"`
+	Profiles                []symbolObject `json:"Profiles"`
+	ControlAddIns           []symbolObject `json:"ControlAddIns"`
+	Entitlements            []symbolObject `json:"Entitlements"`
+}
+
+// scanAppPackage extracts the BC objects described by the
+// SymbolReference.json in the contents of a compiled .app package, so apps can
+// be inventoried without their source code.
+func scanAppPackage(data []byte, filePath string) (*FileResult, error) {
+	symbols, err := readSymbolReference(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var root symbolNamespace
+	if err := json.Unmarshal(symbols, &root); err != nil {
+		return nil, fmt.Errorf("invalid SymbolReference.json: %w", err)
+	}
+
+	result := &FileResult{}
+	collectSymbolObjects(&root, "", filePath, &result.Objects)
+
+	var app symbolApp
+	if err := json.Unmarshal(symbols, &app); err == nil && app.AppID != "" {
+		setApp(result, &AppInfo{ID: app.AppID, Name: app.Name, Publisher: app.Publisher, Version: app.Version})
+	}
+	return result, nil
+}
+
+// readSymbolReference returns the SymbolReference.json document stored in a
+// .app package, with any byte order mark removed.
+func readSymbolReference(data []byte) ([]byte, error) {
+	archive := data
+	if bytes.HasPrefix(data, navxMagic) {
+		if len(data) < 8 {
+			return nil, errors.New("truncated NAVX header")
+		}
+		headerLen := binary.LittleEndian.Uint32(data[4:8])
+		if int64(headerLen) > int64(len(data)) {
+			return nil, fmt.Errorf("NAVX header length %d exceeds file size", headerLen)
+		}
+		archive = data[headerLen:]
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid app package: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if !strings.EqualFold(f.Name, "SymbolReference.json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.TrimPrefix(content, utf8BOM), nil
+	}
+
+	return nil, errors.New("app package does not contain SymbolReference.json")
+}
+
+// collectSymbolObjects appends the objects declared in ns and its nested
+// namespaces to objects.
+func collectSymbolObjects(ns *symbolNamespace, namespace, filePath string, objects *[]BCObject) {
+	groups := []struct {
+		objType string
+		entries []symbolObject
+	}{
+		{"table", ns.Tables},
+		{"tableextension", ns.TableExtensions},
+		{"page", ns.Pages},
+		{"pageextension", ns.PageExtensions},
+		{"report", ns.Reports},
+		{"reportextension", ns.ReportExtensions},
+		{"codeunit", ns.Codeunits},
+		{"xmlport", ns.XmlPorts},
+		{"query", ns.Queries},
+		{"enum", ns.EnumTypes},
+		{"enumextension", ns.EnumExtensionTypes},
+		{"interface", ns.Interfaces},
+		{"permissionset", ns.PermissionSets},
+		{"permissionsetextension", ns.PermissionSetExtensions},
+		{"profile", ns.Profiles},
+		{"controladdin", ns.ControlAddIns},
+		{"entitlement", ns.Entitlements},
+	}
+
+	for _, group := range groups {
+		for _, entry := range group.entries {
+			id := ""
+			if objectTypesWithID[group.objType] {
+				id = strconv.Itoa(entry.ID)
+			}
+			var props Properties
+			for _, p := range entry.Properties {
+				if props == nil {
+					props = make(Properties)
+				}
+				props.set(p.Name, p.Value)
+			}
+			*objects = append(*objects, BCObject{
+				Type:          group.objType,
+				ID:            id,
+				Name:          entry.Name,
+				Namespace:     namespace,
+				QualifiedName: qualifiedName(namespace, entry.Name),
+				Extends:       entry.TargetObject,
+				Implements:    entry.ImplementedInterfaces,
+				FilePath:      filePath,
+				Properties:    props,
+				Obsolete:      obsoleteFromProperties(props),
+			})
+		}
+	}
+
+	for i := range ns.Namespaces {
+		child := &ns.Namespaces[i]
+		childName := child.Name
+		if namespace != "" {
+			childName = namespace + "." + child.Name
+		}
+		collectSymbolObjects(child, childName, filePath, objects)
+	}
+}
@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSymbolReference = `{
+  "AppId": "00000000-0000-0000-0000-000000000001",
+  "Name": "Sample App",
+  "Publisher": "Contoso",
+  "Version": "1.0.0.0",
+  "Tables": [{ "Id": 50100, "Name": "Sample Table", "Properties": [{ "Name": "TableType", "Value": "Temporary" }] }],
+  "Codeunits": [{ "Id": 50100, "Name": "Sample Mgt", "ImplementedInterfaces": ["ISample"] }],
+  "Interfaces": [{ "Name": "ISample" }],
+  "Namespaces": [{
+    "Name": "Contoso",
+    "Namespaces": [{
+      "Name": "Sales",
+      "TableExtensions": [{ "Id": 50101, "Name": "Customer Ext", "TargetObject": "Customer" }]
+    }]
+  }]
+}`
+
+// buildAppPackage returns a NAVX-headed zip archive holding symbols as SymbolReference.json.
+func buildAppPackage(t *testing.T, symbols string) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("SymbolReference.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Real packages write the JSON with a byte order mark
+	if _, err := w.Write(append(append([]byte(nil), utf8BOM...), symbols...)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header := make([]byte, 40)
+	copy(header, navxMagic)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(header)))
+	copy(header[36:], navxMagic)
+
+	return append(header, archive.Bytes()...)
+}
+
+func TestScanDirectoryAppPackage(t *testing.T) {
+	tmpDir := t.TempDir()
+	appFile := filepath.Join(tmpDir, "Contoso_Sample App_1.0.0.0.app")
+	if err := os.WriteFile(appFile, buildAppPackage(t, testSymbolReference), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A single package passed as root is scanned without IncludeApps
+	result, err := ScanDirectory(appFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", result.Diagnostics)
+	}
+
+	expected := []BCObject{
+		{Type: "table", ID: "50100", Name: "Sample Table", QualifiedName: "Sample Table"},
+		{Type: "codeunit", ID: "50100", Name: "Sample Mgt", QualifiedName: "Sample Mgt", Implements: []string{"ISample"}},
+		{Type: "interface", ID: "", Name: "ISample", QualifiedName: "ISample"},
+		{Type: "tableextension", ID: "50101", Name: "Customer Ext", Namespace: "Contoso.Sales",
+			QualifiedName: `Contoso.Sales."Customer Ext"`, Extends: "Customer"},
+	}
+
+	if len(result.Objects) != len(expected) {
+		t.Fatalf("expected %d objects, got %d: %+v", len(expected), len(result.Objects), result.Objects)
+	}
+	for i, exp := range expected {
+		obj := result.Objects[i]
+		if obj.Type != exp.Type || obj.ID != exp.ID || obj.Name != exp.Name ||
+			obj.Namespace != exp.Namespace || obj.QualifiedName != exp.QualifiedName || obj.Extends != exp.Extends {
+			t.Errorf("object %d: expected %+v, got %+v", i, exp, obj)
+		}
+		if len(obj.Implements) != len(exp.Implements) {
+			t.Errorf("object %d: expected implements %v, got %v", i, exp.Implements, obj.Implements)
+		}
+		if obj.FilePath != appFile {
+			t.Errorf("object %d: expected file path %s, got %s", i, appFile, obj.FilePath)
+		}
+		if obj.App == nil || obj.App.ID != "00000000-0000-0000-0000-000000000001" || obj.App.Label() != "Sample App (Contoso) 1.0.0.0" {
+			t.Errorf("object %d: unexpected app %+v", i, obj.App)
+		}
+	}
+
+	if tableType := result.Objects[0].Properties.Get("tabletype"); tableType != "Temporary" {
+		t.Errorf("expected TableType Temporary, got %q", tableType)
+	}
+}
+
+func TestScanInvalidAppPackage(t *testing.T) {
+	tmpDir := t.TempDir()
+	appFile := filepath.Join(tmpDir, "broken.app")
+	data := []byte("NAVX not really a package")
+	if err := os.WriteFile(appFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sourceResult, err := ScanSources(context.Background(), []Source{{Path: "broken.app", Data: data}}, Options{IncludeApps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sourceResult.Objects) != 0 || len(sourceResult.Diagnostics) != 1 || sourceResult.Diagnostics[0].Code != CodePackageError {
+		t.Errorf("expected one package-error diagnostic from ScanSources, got %+v", sourceResult.Diagnostics)
+	}
+
+	result, err := ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, IncludeApps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Code != CodePackageError {
+		t.Errorf("expected one package-error diagnostic, got %+v", result.Diagnostics)
+	}
+}
+
+func TestScanDirectoryIncludeApps(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.al"), []byte(`table 50200 "Source Table" { }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	packages := filepath.Join(tmpDir, ".alpackages")
+	if err := os.Mkdir(packages, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(packages, "dep.app"), buildAppPackage(t, testSymbolReference), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 {
+		t.Errorf("without IncludeApps: expected 1 object, got %d", len(result.Objects))
+	}
+
+	result, err = ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, IncludeApps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 5 {
+		t.Errorf("with IncludeApps: expected 5 objects, got %d", len(result.Objects))
+	}
+
+	// A single package given as root is always scanned
+	result, err = ScanDirectoryContext(context.Background(), filepath.Join(packages, "dep.app"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 4 {
+		t.Errorf("package as root: expected 4 objects, got %d", len(result.Objects))
+	}
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// CALProperties holds the OBJECT-PROPERTIES of an object read from a legacy
+// C/AL text export.
+type CALProperties struct {
+	Date        string `json:"date,omitempty"`
+	Time        string `json:"time,omitempty"`
+	Modified    bool   `json:"modified"`
+	VersionList string `json:"versionList,omitempty"`
+}
+
+// calObjectTypes maps C/AL object types (lowercased) to the object types used
+// for AL. MenuSuite has no AL counterpart and keeps its own type.
+var calObjectTypes = map[string]string{
+	"table":     "table",
+	"page":      "page",
+	"report":    "report",
+	"codeunit":  "codeunit",
+	"xmlport":   "xmlport",
+	"query":     "query",
+	"menusuite": "menusuite",
+}
+
+// calHeaderPrefix starts every object in a C/AL text export, e.g. "OBJECT Table 18 Customer".
+const calHeaderPrefix = "OBJECT "
+
+// isCALExport reports whether src looks like a C/AL text export, i.e. its
+// first non-blank line is an OBJECT header.
+func isCALExport(src []byte) bool {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(src, utf8BOM), " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte(calHeaderPrefix))
+}
+
+// scanCALSource extracts objects from a C/AL text export. The export format
+// is line oriented: every object starts with an unindented OBJECT header and
+// ends with an unindented closing brace, and its OBJECT-PROPERTIES section
+// holds one Name=Value; pair per line.
+func scanCALSource(src []byte, filePath string) *FileResult {
+	// NAV exports text in the OEM code page unless it was converted to UTF-8
+	if !utf8.Valid(src) {
+		if decoded, err := charmap.CodePage850.NewDecoder().Bytes(src); err == nil {
+			src = decoded
+		}
+	}
+	src = bytes.TrimPrefix(src, utf8BOM)
+
+	result := &FileResult{}
+	var current *BCObject
+	inObjectProperties := false
+	offset := 0
+	lineNo := 0
+
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(make([]byte, 0, 64*1024), len(src)+1)
+	for sc.Scan() {
+		raw := sc.Text()
+		lineNo++
+		lineOffset := offset
+		offset += len(raw) + 1
+		line := strings.TrimRight(raw, "\r")
+
+		switch {
+		case strings.HasPrefix(line, calHeaderPrefix):
+			if current != nil {
+				result.Objects = append(result.Objects, *current)
+			}
+			current = parseCALHeader(line, filePath, lineNo, lineOffset, result)
+			inObjectProperties = false
+
+		case current == nil:
+			continue
+
+		case line == "}":
+			// Unindented closing brace ends the current object
+			current.EndLine = lineNo
+			current.EndOffset = lineOffset + 1
+			result.Objects = append(result.Objects, *current)
+			current = nil
+
+		case strings.TrimSpace(line) == "OBJECT-PROPERTIES":
+			inObjectProperties = true
+			current.CAL = &CALProperties{}
+
+		case inObjectProperties && strings.TrimSpace(line) == "}":
+			inObjectProperties = false
+
+		case inObjectProperties:
+			setCALProperty(current.CAL, strings.TrimSpace(line))
+		}
+	}
+
+	if current != nil {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     current.Line,
+			Column:   current.Column,
+			Severity: SeverityWarning,
+			Code:     CodeUnclosedBlock,
+			Message:  fmt.Sprintf("%s %q has no closing brace", current.Type, current.Name),
+		})
+		current.EndLine = lineNo
+		current.EndOffset = len(src)
+		result.Objects = append(result.Objects, *current)
+	}
+
+	applyLineMetrics(result, filePath, classifyTextLines(src))
+	return result
+}
+
+// parseCALHeader parses an "OBJECT <Type> <ID> <Name>" line. Unknown object
+// types are reported as diagnostics and yield a nil object.
+func parseCALHeader(line, filePath string, lineNo, offset int, result *FileResult) *BCObject {
+	fields := strings.SplitN(strings.TrimPrefix(line, calHeaderPrefix), " ", 3)
+	if len(fields) < 3 {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     lineNo,
+			Column:   1,
+			Severity: SeverityWarning,
+			Code:     CodeUnknownObject,
+			Message:  fmt.Sprintf("malformed C/AL object header %q", line),
+		})
+		return nil
+	}
+
+	objType, ok := calObjectTypes[strings.ToLower(fields[0])]
+	if !ok {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     lineNo,
+			Column:   1,
+			Severity: SeverityWarning,
+			Code:     CodeUnknownObject,
+			Message:  fmt.Sprintf("unsupported C/AL object type %q", fields[0]),
+		})
+		return nil
+	}
+
+	name := strings.TrimSpace(fields[2])
+	return &BCObject{
+		Type:          objType,
+		ID:            fields[1],
+		Name:          name,
+		QualifiedName: name,
+		FilePath:      filePath,
+		Line:          lineNo,
+		Column:        1,
+		Offset:        offset,
+		EndLine:       lineNo,
+		EndOffset:     offset + len(line),
+	}
+}
+
+// setCALProperty applies a "Name=Value;" line from OBJECT-PROPERTIES.
+func setCALProperty(props *CALProperties, line string) {
+	name, value, ok := strings.Cut(strings.TrimSuffix(line, ";"), "=")
+	if !ok {
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "date":
+		props.Date = value
+	case "time":
+		props.Time = value
+	case "modified":
+		props.Modified = strings.EqualFold(value, "yes")
+	case "version list":
+		props.VersionList = value
+	}
+}
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanCALExport(t *testing.T) {
+	path := filepath.Join("..", "..", "testdata", "cal", "navobjects.txt")
+	result, err := ScanFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		objType, id, name string
+		line, endLine     int
+		props             CALProperties
+	}{
+		{"table", "18", "Customer", 1, 31, CALProperties{Date: "25.10.18", Time: "12:00:00", Modified: true, VersionList: "NAVW111.00,CUST1.0"}},
+		{"page", "21", "Customer Card", 33, 53, CALProperties{Date: "25.10.18", Time: "12:00:00", Modified: false, VersionList: "NAVW111.00"}},
+		{"codeunit", "50000", "Sales Customizations", 55, 76, CALProperties{Date: "01.02.19", Time: "09:30:00", Modified: true, VersionList: "CUST1.0"}},
+		{"menusuite", "1090", "Dept - Company", 78, 92, CALProperties{Date: "25.10.18", Time: "12:00:00", VersionList: "NAVW111.00"}},
+	}
+
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", result.Diagnostics)
+	}
+	if len(result.Objects) != len(expected) {
+		t.Fatalf("expected %d objects, got %d", len(expected), len(result.Objects))
+	}
+
+	for i, exp := range expected {
+		obj := result.Objects[i]
+		if obj.Type != exp.objType || obj.ID != exp.id || obj.Name != exp.name {
+			t.Errorf("object %d: expected %s %s %q, got %s %s %q", i, exp.objType, exp.id, exp.name, obj.Type, obj.ID, obj.Name)
+		}
+		if obj.Line != exp.line || obj.EndLine != exp.endLine {
+			t.Errorf("object %d: expected lines %d-%d, got %d-%d", i, exp.line, exp.endLine, obj.Line, obj.EndLine)
+		}
+		if obj.CAL == nil {
+			t.Errorf("object %d: expected C/AL properties", i)
+			continue
+		}
+		if *obj.CAL != exp.props {
+			t.Errorf("object %d: expected properties %+v, got %+v", i, exp.props, *obj.CAL)
+		}
+		if obj.Lines.Physical != exp.endLine-exp.line+1 {
+			t.Errorf("object %d: expected %d physical lines, got %d", i, exp.endLine-exp.line+1, obj.Lines.Physical)
+		}
+	}
+
+	if result.Metrics == nil || result.Metrics.Lines.Physical != 92 || result.Metrics.Lines.Blank != 8 {
+		t.Errorf("expected 92 lines with 8 blank, got %+v", result.Metrics)
+	}
+}
+
+func TestScanCALExportCodePage(t *testing.T) {
+	// "Kundenübersicht" encoded in code page 850, as written by NAV
+	content := []byte("OBJECT Page 50000 Kunden\x81bersicht\n{\n}\n")
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "export.txt")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "Kundenübersicht" {
+		t.Errorf("expected decoded name Kundenübersicht, got %+v", result.Objects)
+	}
+}
+
+func TestScanDirectorySkipsPlainText(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "notes.txt"), []byte("table 50100 \"Not AL\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "nav.txt"), []byte("OBJECT Table 50100 Legacy\n{\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, IncludeCAL: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "Legacy" {
+		t.Errorf("expected only the C/AL object, got %+v", result.Objects)
+	}
+}
+
+func TestScanDirectoryIncludeCAL(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "Customer.Table.al"), []byte("table 50100 \"Customer Ext\"\n{\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nav := filepath.Join(tmpDir, "nav.txt")
+	if err := os.WriteFile(nav, []byte("OBJECT Table 18 Customer\n{\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanDirectory(tmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "Customer Ext" {
+		t.Errorf("without IncludeCAL: expected only the AL object, got %+v", result.Objects)
+	}
+
+	result, err = ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, IncludeCAL: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 2 {
+		t.Errorf("with IncludeCAL: expected 2 objects, got %d", len(result.Objects))
+	}
+
+	// A single export passed as root is always read
+	result, err = ScanDirectory(nav, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Name != "Customer" {
+		t.Errorf("expected the C/AL object of a single export, got %+v", result.Objects)
+	}
+}
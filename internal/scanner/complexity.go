@@ -0,0 +1,182 @@
+package scanner
+
+// codeMetrics walks the statements of a procedure or trigger body, counting
+// its cyclomatic complexity and the deepest nesting of control structures.
+//
+// Complexity starts at 1 and adds one for every if, while, for, foreach and
+// repeat, every case branch (the else branch excluded) and every and/or
+// operator. Nesting is 0 for straight-line code, 1 inside a top-level if,
+// loop or case, and so on; an else if does not add a level.
+type codeMetrics struct {
+	toks       []Token
+	pos        int
+	complexity int
+	nesting    int
+}
+
+// measureCode computes the complexity and nesting of the procedure or trigger
+// declared at body[i]. It returns zeros for declarations without a body, such
+// as interface procedures.
+func measureCode(body []Token, i int) (complexity, nesting int) {
+	for j := i + 1; j < len(body); j++ {
+		tok := body[j]
+		switch {
+		case tok.IsKeyword("begin"):
+			m := &codeMetrics{toks: body, pos: j, complexity: 1}
+			m.statement(0)
+			return m.complexity, m.nesting
+		case tok.IsKeyword("procedure") || tok.IsKeyword("trigger") || tok.IsPunct("{") || tok.IsPunct("}"):
+			return 0, 0
+		}
+	}
+	return 0, 0
+}
+
+func (m *codeMetrics) cur() Token {
+	if m.pos < len(m.toks) {
+		return m.toks[m.pos]
+	}
+	return Token{Kind: TokenEOF}
+}
+
+func (m *codeMetrics) atEnd() bool {
+	return m.pos >= len(m.toks)
+}
+
+// enter records that a control structure's statements are nested at level.
+func (m *codeMetrics) enter(level int) {
+	if level > m.nesting {
+		m.nesting = level
+	}
+}
+
+// statement parses one statement whose enclosing control structures are
+// nested depth levels deep. Simple statements stop before their terminator.
+func (m *codeMetrics) statement(depth int) {
+	tok := m.cur()
+	switch {
+	case tok.IsKeyword("begin"):
+		m.pos++
+		m.statements(depth, "end")
+		m.pos++
+
+	case tok.IsKeyword("if"):
+		m.complexity++
+		m.enter(depth + 1)
+		m.pos++
+		m.expression("then")
+		m.pos++
+		m.statement(depth + 1)
+		if m.cur().IsKeyword("else") {
+			m.pos++
+			if m.cur().IsKeyword("if") {
+				m.statement(depth)
+			} else {
+				m.statement(depth + 1)
+			}
+		}
+
+	case tok.IsKeyword("while") || tok.IsKeyword("for") || tok.IsKeyword("foreach"):
+		m.complexity++
+		m.enter(depth + 1)
+		m.pos++
+		m.expression("do")
+		m.pos++
+		m.statement(depth + 1)
+
+	case tok.IsKeyword("with"):
+		m.pos++
+		m.expression("do")
+		m.pos++
+		m.statement(depth)
+
+	case tok.IsKeyword("repeat"):
+		m.complexity++
+		m.enter(depth + 1)
+		m.pos++
+		m.statements(depth+1, "until")
+		m.pos++
+		m.expression("else", "end", "until")
+
+	case tok.IsKeyword("case"):
+		m.enter(depth + 1)
+		m.pos++
+		m.expression("of")
+		m.pos++
+		m.caseBranches(depth + 1)
+
+	default:
+		m.expression("else", "end", "until")
+	}
+}
+
+// statements parses statements up to, but not including, the keyword that
+// closes the list.
+func (m *codeMetrics) statements(depth int, closer string) {
+	for !m.atEnd() && !m.cur().IsKeyword(closer) {
+		if m.cur().IsPunct(";") {
+			m.pos++
+			continue
+		}
+		start := m.pos
+		m.statement(depth)
+		if m.pos == start {
+			// Stray else or until; skip it rather than loop forever
+			m.pos++
+		}
+	}
+}
+
+// caseBranches parses the branches of a case statement after its of keyword,
+// including the closing end.
+func (m *codeMetrics) caseBranches(depth int) {
+	for !m.atEnd() {
+		tok := m.cur()
+		switch {
+		case tok.IsKeyword("end"):
+			m.pos++
+			return
+		case tok.IsKeyword("else"):
+			m.pos++
+			m.statements(depth, "end")
+		case tok.IsPunct(";"):
+			m.pos++
+		default:
+			m.expression(":", "end", "else")
+			if !m.cur().IsPunct(":") {
+				continue
+			}
+			m.complexity++
+			m.pos++
+			m.statement(depth)
+		}
+	}
+}
+
+// expression skips tokens up to the first of the stop keywords, a : when
+// listed, or a semicolon outside parentheses, counting and/or operators.
+func (m *codeMetrics) expression(stops ...string) {
+	depth := 0
+	for ; !m.atEnd(); m.pos++ {
+		tok := m.cur()
+		switch {
+		case tok.IsPunct("(") || tok.IsPunct("["):
+			depth++
+		case tok.IsPunct(")") || tok.IsPunct("]"):
+			depth--
+		case tok.IsKeyword("and") || tok.IsKeyword("or"):
+			m.complexity++
+		}
+		if depth > 0 {
+			continue
+		}
+		if tok.IsPunct(";") {
+			return
+		}
+		for _, stop := range stops {
+			if tok.IsKeyword(stop) || stop == ":" && tok.IsPunct(":") {
+				return
+			}
+		}
+	}
+}
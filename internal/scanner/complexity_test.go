@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestScanFileComplexity(t *testing.T) {
+	content := `codeunit 50100 "Sales Logic"
+{
+    procedure Straight()
+    var
+        x: Integer;
+    begin
+        x := 1;
+        Message('%1', x);
+    end;
+
+    procedure Branches(a: Boolean; b: Boolean): Integer
+    begin
+        if a and b then
+            exit(1)
+        else if a or b then
+            exit(2)
+        else
+            exit(3);
+    end;
+
+    local procedure Loops(var Rec: Record Customer)
+    var
+        i: Integer;
+    begin
+        for i := 1 to 10 do begin
+            while i < 5 do
+                i += 1;
+        end;
+        if Rec.FindSet() then
+            repeat
+                case Rec.Blocked of
+                    Rec.Blocked::" ":
+                        if Rec.Name = '' then
+                            exit;
+                    Rec.Blocked::Ship, Rec.Blocked::Invoice:
+                        begin
+                            Rec.Validate(Name, 'x');
+                        end;
+                    else
+                        exit;
+                end;
+            until Rec.Next() = 0;
+    end;
+
+    trigger OnRun()
+    begin
+        case true of
+            IsReady():
+                Run()
+        end
+    end;
+}
+
+interface "ISales"
+{
+    procedure Post(): Boolean;
+    procedure Release();
+}
+`
+	result := scanSource([]byte(content), "test.al", nil)
+	if len(result.Objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(result.Objects))
+	}
+
+	expected := []struct {
+		name       string
+		complexity int
+		nesting    int
+	}{
+		{"Straight", 1, 0},
+		// if, and, else if, or
+		{"Branches", 5, 1},
+		// for, while, if, repeat, two case branches, nested if
+		{"Loops", 8, 4},
+		{"OnRun", 2, 1},
+	}
+	members := result.Objects[0].Members
+	if len(members) != len(expected) {
+		t.Fatalf("expected %d members, got %d: %+v", len(expected), len(members), members)
+	}
+	for i, exp := range expected {
+		m := members[i]
+		if m.Name != exp.name || m.Complexity != exp.complexity || m.Nesting != exp.nesting {
+			t.Errorf("member %d: expected %s complexity %d nesting %d, got %s complexity %d nesting %d",
+				i, exp.name, exp.complexity, exp.nesting, m.Name, m.Complexity, m.Nesting)
+		}
+	}
+
+	// Interface procedures have no body to measure
+	for _, m := range result.Objects[1].Members {
+		if m.Complexity != 0 || m.Nesting != 0 {
+			t.Errorf("expected no complexity for %s, got %d/%d", m.Name, m.Complexity, m.Nesting)
+		}
+	}
+}
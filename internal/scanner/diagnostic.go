@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"fmt"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity string
+
+const (
+	// SeverityError marks a problem that made the scanner skip input, so counts may be incomplete.
+	SeverityError Severity = "error"
+	// SeverityWarning marks suspicious input that was still scanned.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic codes reported by the scanner.
+const (
+	CodeWalkError     = "walk-error"     // a directory or file could not be listed
+	CodeReadError     = "read-error"     // a file could not be read
+	CodeLexError      = "lex-error"      // a malformed token, e.g. an unterminated string
+	CodeUnclosedBlock = "unclosed-block" // an object body is missing its closing brace
+	CodePackageError  = "package-error"  // a .app package is malformed or lacks symbol metadata
+	CodeUnknownObject = "unknown-object" // a C/AL object header is malformed or of an unsupported type
+	CodePreprocessor  = "preprocessor"   // a malformed or unbalanced #if/#elif/#else/#endif directive
+	CodeManifestError = "manifest-error" // an app.json file could not be read
+)
+
+// Diagnostic describes a problem found while scanning. Line and Column are
+// 1-based and zero when the problem concerns the file as a whole.
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+// String formats the diagnostic as "file:line:col: severity: message [code]".
+func (d Diagnostic) String() string {
+	pos := d.File
+	if d.Line > 0 {
+		pos = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", pos, d.Severity, d.Message, d.Code)
+}
+
+// CountDiagnostics returns the number of diagnostics with the given severity.
+func CountDiagnostics(diagnostics []Diagnostic, severity Severity) int {
+	count := 0
+	for _, d := range diagnostics {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,336 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind identifies the lexical category of a Token.
+type TokenKind int
+
+const (
+	// TokenEOF marks the end of the input.
+	TokenEOF TokenKind = iota
+	// TokenIdent is a plain identifier or keyword (e.g. table, begin, MyVar).
+	TokenIdent
+	// TokenQuotedIdent is a double-quoted identifier (e.g. "Sales Header").
+	TokenQuotedIdent
+	// TokenString is a single-quoted text literal (e.g. 'It''s').
+	TokenString
+	// TokenNumber is an integer or decimal literal.
+	TokenNumber
+	// TokenPunct is an operator or punctuation character (e.g. {, ;, :=, ..).
+	TokenPunct
+	// TokenComment is a line (//) or block (/* */) comment.
+	TokenComment
+	// TokenDirective is a preprocessor directive line (e.g. #if CLEAN24).
+	TokenDirective
+	// TokenIllegal is a malformed token such as an unterminated string.
+	TokenIllegal
+)
+
+// String returns a human-readable name for the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "EOF"
+	case TokenIdent:
+		return "Ident"
+	case TokenQuotedIdent:
+		return "QuotedIdent"
+	case TokenString:
+		return "String"
+	case TokenNumber:
+		return "Number"
+	case TokenPunct:
+		return "Punct"
+	case TokenComment:
+		return "Comment"
+	case TokenDirective:
+		return "Directive"
+	case TokenIllegal:
+		return "Illegal"
+	}
+	return "Unknown"
+}
+
+// Token is a single lexical element of AL source code.
+type Token struct {
+	Kind TokenKind
+	// Text is the raw source text of the token, including quotes and comment markers.
+	Text string
+	// Value is the unquoted value for identifiers and strings ('' unescaped);
+	// for all other kinds it equals Text.
+	Value string
+	// Line and Column are 1-based; Column counts bytes.
+	Line   int
+	Column int
+	// Offset is the 0-based byte offset of the token in the source.
+	Offset int
+}
+
+// IsKeyword reports whether the token is a plain identifier matching kw, case-insensitively.
+func (t Token) IsKeyword(kw string) bool {
+	return t.Kind == TokenIdent && strings.EqualFold(t.Text, kw)
+}
+
+// IsPunct reports whether the token is the punctuation p.
+func (t Token) IsPunct(p string) bool {
+	return t.Kind == TokenPunct && t.Text == p
+}
+
+// utf8BOM is the byte order mark some editors write at the start of AL files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// multiCharPuncts lists the multi-character operators recognized by the lexer.
+var multiCharPuncts = []string{":=", "+=", "-=", "*=", "/=", "<>", "<=", ">=", "..", "::"}
+
+// Lexer splits AL source code into tokens.
+type Lexer struct {
+	src    []byte
+	pos    int
+	line   int
+	col    int
+	bol    bool // only whitespace seen since the start of the current line
+	errors []LexError
+}
+
+// LexError describes a malformed token found while lexing.
+type LexError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the error interface.
+func (e LexError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// NewLexer creates a lexer for the given source.
+func NewLexer(src []byte) *Lexer {
+	lx := &Lexer{src: src, line: 1, col: 1, bol: true}
+	// A leading byte order mark is invisible in editors, so the first token
+	// still starts at column 1; offsets remain byte offsets into src.
+	if bytes.HasPrefix(src, utf8BOM) {
+		lx.pos = len(utf8BOM)
+	}
+	return lx
+}
+
+// Tokenize returns all tokens in src, including comments and directives,
+// terminated by a TokenEOF token.
+func Tokenize(src []byte) []Token {
+	lx := NewLexer(src)
+	var tokens []Token
+	for {
+		tok := lx.Next()
+		tokens = append(tokens, tok)
+		if tok.Kind == TokenEOF {
+			return tokens
+		}
+	}
+}
+
+// Next returns the next token from the input.
+func (lx *Lexer) Next() Token {
+	lx.skipWhitespace()
+
+	start, line, col := lx.pos, lx.line, lx.col
+	atLineStart := lx.bol
+	lx.bol = false
+
+	if lx.pos >= len(lx.src) {
+		return Token{Kind: TokenEOF, Line: line, Column: col, Offset: start}
+	}
+
+	c := lx.src[lx.pos]
+	var kind TokenKind
+	value := ""
+
+	switch {
+	case c == '/' && lx.peek(1) == '/':
+		kind = TokenComment
+		lx.skipToEOL()
+	case c == '/' && lx.peek(1) == '*':
+		kind = TokenComment
+		lx.advance(2)
+		if end := bytes.Index(lx.src[lx.pos:], []byte("*/")); end >= 0 {
+			lx.advance(end + 2)
+		} else {
+			kind = TokenIllegal
+			lx.advance(len(lx.src) - lx.pos)
+			lx.errorf(line, col, "unterminated block comment")
+		}
+	case c == '#' && atLineStart:
+		kind = TokenDirective
+		lx.skipToEOL()
+	case c == '"':
+		kind = TokenQuotedIdent
+		lx.advance(1)
+		end := bytes.IndexAny(lx.src[lx.pos:], "\"\n")
+		if end >= 0 && lx.src[lx.pos+end] == '"' {
+			value = string(lx.src[lx.pos : lx.pos+end])
+			lx.advance(end + 1)
+		} else {
+			kind = TokenIllegal
+			lx.skipToEOL()
+			lx.errorf(line, col, "unterminated quoted identifier")
+		}
+	case c == '\'':
+		kind = TokenString
+		var ok bool
+		value, ok = lx.scanString()
+		if !ok {
+			kind = TokenIllegal
+			lx.errorf(line, col, "unterminated string literal")
+		}
+	case isDigit(c):
+		kind = TokenNumber
+		lx.scanNumber()
+	case isIdentStart(lx.src[lx.pos:]):
+		kind = TokenIdent
+		lx.scanIdent()
+	default:
+		kind = TokenPunct
+		n := 1
+		for _, p := range multiCharPuncts {
+			if bytes.HasPrefix(lx.src[lx.pos:], []byte(p)) {
+				n = len(p)
+				break
+			}
+		}
+		if n == 1 {
+			// Advance a whole rune so stray multi-byte characters stay intact
+			_, n = utf8.DecodeRune(lx.src[lx.pos:])
+		}
+		lx.advance(n)
+	}
+
+	text := string(lx.src[start:lx.pos])
+	if kind != TokenQuotedIdent && kind != TokenString {
+		value = text
+	}
+
+	return Token{Kind: kind, Text: text, Value: value, Line: line, Column: col, Offset: start}
+}
+
+// Errors returns the lexical errors encountered so far.
+func (lx *Lexer) Errors() []LexError {
+	return lx.errors
+}
+
+func (lx *Lexer) errorf(line, col int, msg string) {
+	lx.errors = append(lx.errors, LexError{Line: line, Column: col, Message: msg})
+}
+
+func (lx *Lexer) peek(n int) byte {
+	if lx.pos+n < len(lx.src) {
+		return lx.src[lx.pos+n]
+	}
+	return 0
+}
+
+// advance moves forward n bytes, keeping line and column up to date.
+func (lx *Lexer) advance(n int) {
+	for i := 0; i < n && lx.pos < len(lx.src); i++ {
+		if lx.src[lx.pos] == '\n' {
+			lx.line++
+			lx.col = 1
+			lx.bol = true
+		} else {
+			lx.col++
+		}
+		lx.pos++
+	}
+}
+
+func (lx *Lexer) skipWhitespace() {
+	for lx.pos < len(lx.src) {
+		switch lx.src[lx.pos] {
+		case ' ', '\t', '\r', '\n', '\f', '\v':
+			lx.advance(1)
+		default:
+			// Treat a stray byte order mark, e.g. from concatenated files,
+			// like whitespace
+			if bytes.HasPrefix(lx.src[lx.pos:], utf8BOM) {
+				lx.advance(len(utf8BOM))
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (lx *Lexer) skipToEOL() {
+	end := bytes.IndexByte(lx.src[lx.pos:], '\n')
+	if end < 0 {
+		end = len(lx.src) - lx.pos
+	}
+	// Leave a trailing \r out of the token text
+	if end > 0 && lx.src[lx.pos+end-1] == '\r' {
+		end--
+	}
+	lx.advance(end)
+}
+
+// scanString consumes a single-quoted literal and returns its unescaped value.
+// AL text literals cannot span lines, so it reports false if the line or the
+// input ends before the closing quote.
+func (lx *Lexer) scanString() (string, bool) {
+	var sb strings.Builder
+	lx.advance(1)
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		if c == '\'' {
+			if lx.peek(1) == '\'' {
+				sb.WriteByte('\'')
+				lx.advance(2)
+				continue
+			}
+			lx.advance(1)
+			return sb.String(), true
+		}
+		if c == '\n' {
+			break
+		}
+		sb.WriteByte(c)
+		lx.advance(1)
+	}
+	return sb.String(), false
+}
+
+func (lx *Lexer) scanNumber() {
+	for lx.pos < len(lx.src) && isDigit(lx.src[lx.pos]) {
+		lx.advance(1)
+	}
+	// Decimal part, but not the range operator in 1..10
+	if lx.pos < len(lx.src) && lx.src[lx.pos] == '.' && isDigit(lx.peek(1)) {
+		lx.advance(1)
+		for lx.pos < len(lx.src) && isDigit(lx.src[lx.pos]) {
+			lx.advance(1)
+		}
+	}
+}
+
+func (lx *Lexer) scanIdent() {
+	for lx.pos < len(lx.src) {
+		r, size := utf8.DecodeRune(lx.src[lx.pos:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return
+		}
+		lx.advance(size)
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(b []byte) bool {
+	r, _ := utf8.DecodeRune(b)
+	return r == '_' || unicode.IsLetter(r)
+}
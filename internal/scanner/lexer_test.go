@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	src := `table 50100 "My Table" // trailing
+{
+    Caption = 'It''s /* not a comment';
+    /* block */ x := 1..10;
+    y += 3.14;
+}
+#if CLEAN24
+`
+
+	expected := []struct {
+		kind  TokenKind
+		text  string
+		value string
+	}{
+		{TokenIdent, "table", "table"},
+		{TokenNumber, "50100", "50100"},
+		{TokenQuotedIdent, `"My Table"`, "My Table"},
+		{TokenComment, "// trailing", "// trailing"},
+		{TokenPunct, "{", "{"},
+		{TokenIdent, "Caption", "Caption"},
+		{TokenPunct, "=", "="},
+		{TokenString, `'It''s /* not a comment'`, "It's /* not a comment"},
+		{TokenPunct, ";", ";"},
+		{TokenComment, "/* block */", "/* block */"},
+		{TokenIdent, "x", "x"},
+		{TokenPunct, ":=", ":="},
+		{TokenNumber, "1", "1"},
+		{TokenPunct, "..", ".."},
+		{TokenNumber, "10", "10"},
+		{TokenPunct, ";", ";"},
+		{TokenIdent, "y", "y"},
+		{TokenPunct, "+=", "+="},
+		{TokenNumber, "3.14", "3.14"},
+		{TokenPunct, ";", ";"},
+		{TokenPunct, "}", "}"},
+		{TokenDirective, "#if CLEAN24", "#if CLEAN24"},
+		{TokenEOF, "", ""},
+	}
+
+	tokens := Tokenize([]byte(src))
+	if len(tokens) != len(expected) {
+		for _, tok := range tokens {
+			t.Logf("  %s %q", tok.Kind, tok.Text)
+		}
+		t.Fatalf("expected %d tokens, got %d", len(expected), len(tokens))
+	}
+
+	for i, exp := range expected {
+		tok := tokens[i]
+		if tok.Kind != exp.kind || tok.Text != exp.text || tok.Value != exp.value {
+			t.Errorf("token %d: expected %s %q (%q), got %s %q (%q)",
+				i, exp.kind, exp.text, exp.value, tok.Kind, tok.Text, tok.Value)
+		}
+	}
+}
+
+func TestTokenizePositions(t *testing.T) {
+	tokens := Tokenize([]byte("table 1 \"A\"\n  page"))
+
+	expected := []struct {
+		line, column, offset int
+	}{
+		{1, 1, 0},
+		{1, 7, 6},
+		{1, 9, 8},
+		{2, 3, 14},
+	}
+
+	for i, exp := range expected {
+		tok := tokens[i]
+		if tok.Line != exp.line || tok.Column != exp.column || tok.Offset != exp.offset {
+			t.Errorf("token %d (%q): expected %d:%d@%d, got %d:%d@%d",
+				i, tok.Text, exp.line, exp.column, exp.offset, tok.Line, tok.Column, tok.Offset)
+		}
+	}
+}
+
+func TestTokenizeByteOrderMark(t *testing.T) {
+	// A leading BOM is skipped without taking a column; a stray one is
+	// whitespace three bytes wide, like any other multi-byte character
+	tokens := Tokenize([]byte("\xef\xbb\xbf#if X\ntable\xef\xbb\xbf1"))
+
+	expected := []struct {
+		kind                 TokenKind
+		line, column, offset int
+	}{
+		{TokenDirective, 1, 1, 3},
+		{TokenIdent, 2, 1, 9},
+		{TokenNumber, 2, 9, 17},
+	}
+
+	for i, exp := range expected {
+		tok := tokens[i]
+		if tok.Kind != exp.kind || tok.Line != exp.line || tok.Column != exp.column || tok.Offset != exp.offset {
+			t.Errorf("token %d (%q): expected %s %d:%d@%d, got %s %d:%d@%d",
+				i, tok.Text, exp.kind, exp.line, exp.column, exp.offset, tok.Kind, tok.Line, tok.Column, tok.Offset)
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unterminated string", "x := 'abc\ny := 1;"},
+		{"unterminated quoted identifier", "table 1 \"abc\n{ }"},
+		{"unterminated block comment", "/* never closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lx := NewLexer([]byte(tt.src))
+			illegal := 0
+			for tok := lx.Next(); tok.Kind != TokenEOF; tok = lx.Next() {
+				if tok.Kind == TokenIllegal {
+					illegal++
+				}
+			}
+			if illegal != 1 {
+				t.Errorf("expected 1 illegal token, got %d", illegal)
+			}
+			if len(lx.Errors()) != 1 {
+				t.Errorf("expected 1 lex error, got %d", len(lx.Errors()))
+			}
+		})
+	}
+}
+
+func TestTokenizeDirectiveOnlyAtLineStart(t *testing.T) {
+	tokens := Tokenize([]byte("x # y\n  #endif"))
+
+	if tokens[1].Kind != TokenPunct || tokens[1].Text != "#" {
+		t.Errorf("expected mid-line # to be punctuation, got %s %q", tokens[1].Kind, tokens[1].Text)
+	}
+	if tokens[3].Kind != TokenDirective || tokens[3].Text != "#endif" {
+		t.Errorf("expected #endif directive, got %s %q", tokens[3].Kind, tokens[3].Text)
+	}
+}
@@ -0,0 +1,180 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// manifestFileName is the name of the AL app manifest.
+const manifestFileName = "app.json"
+
+// AppManifest is the subset of an AL app.json used by the scanner.
+type AppManifest struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Publisher           string   `json:"publisher"`
+	Version             string   `json:"version"`
+	PreprocessorSymbols []string `json:"preprocessorSymbols"`
+	// IDRanges are the object ID ranges assigned to the app; IDRange is the
+	// single range used by older app.json files.
+	IDRanges []IDRange `json:"idRanges,omitempty"`
+	IDRange  *IDRange  `json:"idRange,omitempty"`
+	// Dir is the directory containing the app.json file.
+	Dir string `json:"-"`
+}
+
+// IDRange is an inclusive range of object IDs.
+type IDRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Contains reports whether id lies within the range.
+func (r IDRange) Contains(id int) bool {
+	return id >= r.From && id <= r.To
+}
+
+// String formats the range as from..to.
+func (r IDRange) String() string {
+	return fmt.Sprintf("%d..%d", r.From, r.To)
+}
+
+// FormatRanges lists ranges as "50100..50149, 70000..70099".
+func FormatRanges(ranges []IDRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Ranges returns the app's ID ranges, including the legacy idRange.
+func (m *AppManifest) Ranges() []IDRange {
+	ranges := append([]IDRange(nil), m.IDRanges...)
+	if m.IDRange != nil {
+		ranges = append(ranges, *m.IDRange)
+	}
+	return ranges
+}
+
+// Path returns the path of the app.json file.
+func (m *AppManifest) Path() string {
+	return filepath.Join(m.Dir, manifestFileName)
+}
+
+// AppInfo identifies the app an object belongs to.
+type AppInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Publisher string `json:"publisher"`
+	Version   string `json:"version"`
+}
+
+// Label returns a display name for the app, e.g. "Sales (Contoso) 1.0.0.0",
+// or "(no app)" for objects outside any app.
+func (a AppInfo) Label() string {
+	if a == (AppInfo{}) {
+		return "(no app)"
+	}
+	label := a.Name
+	if label == "" {
+		label = a.ID
+	}
+	if a.Publisher != "" {
+		label += " (" + a.Publisher + ")"
+	}
+	if a.Version != "" {
+		label += " " + a.Version
+	}
+	return label
+}
+
+// Info returns the identity of the app described by the manifest.
+func (m *AppManifest) Info() *AppInfo {
+	return &AppInfo{ID: m.ID, Name: m.Name, Publisher: m.Publisher, Version: m.Version}
+}
+
+// LoadManifest reads an app.json file.
+func LoadManifest(path string) (*AppManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseManifest(data, path)
+}
+
+// parseManifest decodes the contents of the app.json file at path.
+func parseManifest(data []byte, path string) (*AppManifest, error) {
+	var manifest AppManifest
+	if err := json.Unmarshal(bytes.TrimPrefix(data, utf8BOM), &manifest); err != nil {
+		return nil, err
+	}
+	manifest.Dir = filepath.Dir(path)
+	return &manifest, nil
+}
+
+// isManifest reports whether path is an app.json file.
+func isManifest(path string) bool {
+	return strings.EqualFold(filepath.Base(path), manifestFileName)
+}
+
+// setApp attributes the objects of a file result to app.
+func setApp(result *FileResult, app *AppInfo) {
+	for i := range result.Objects {
+		result.Objects[i].App = app
+	}
+	for i := range result.InactiveObjects {
+		result.InactiveObjects[i].App = app
+	}
+}
+
+// owningManifest returns the manifest of the nearest app.json in path's
+// directory or one of its parents, looked up in manifests by directory.
+func owningManifest(path string, manifests map[string]*AppManifest) *AppManifest {
+	if len(manifests) == 0 {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	for {
+		if m, ok := manifests[dir]; ok {
+			return m
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// findManifest looks for an app.json in path (if it is a directory) or its
+// closest parent directory containing one. It returns nil if there is none or
+// it cannot be read.
+func findManifest(path string) *AppManifest {
+	dir := path
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	for {
+		manifest, err := LoadManifest(filepath.Join(dir, manifestFileName))
+		if err == nil {
+			return manifest
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
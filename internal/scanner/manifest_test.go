@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/testutil"
+)
+
+func TestScanDirectoryApps(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main/app.json":              `{"id": "11111111-0000-0000-0000-000000000000", "name": "Sales", "publisher": "Contoso", "version": "1.0.0.0"}`,
+		"main/src/Setup.Table.al":    "table 50100 \"Sales Setup\"\n{\n}\n",
+		"test/app.json":              `{"id": "22222222-0000-0000-0000-000000000000", "name": "Sales Tests", "publisher": "Contoso", "version": "1.0.0.0"}`,
+		"test/src/Tests.Codeunit.al": "codeunit 50150 \"Sales Tests\"\n{\n    Subtype = Test;\n}\n",
+		"scripts/Helper.Codeunit.al": "codeunit 50199 \"Helper\"\n{\n}\n",
+	}
+	testutil.WriteFiles(t, root, files)
+
+	result, err := ScanDirectory(root, true)
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+	if len(result.Objects) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(result.Objects))
+	}
+
+	apps := make(map[string]*AppInfo)
+	for _, obj := range result.Objects {
+		apps[obj.Name] = obj.App
+	}
+	if app := apps["Sales Setup"]; app == nil || *app != (AppInfo{ID: "11111111-0000-0000-0000-000000000000", Name: "Sales", Publisher: "Contoso", Version: "1.0.0.0"}) {
+		t.Errorf("unexpected app for Sales Setup: %+v", app)
+	}
+	if app := apps["Sales Tests"]; app == nil || app.Name != "Sales Tests" {
+		t.Errorf("unexpected app for Sales Tests: %+v", app)
+	}
+	if app := apps["Helper"]; app != nil {
+		t.Errorf("expected no app for Helper, got %+v", app)
+	}
+
+	// ScanFile attributes objects to the nearest app.json as well
+	fileResult, err := ScanFile(filepath.Join(root, "test", "src", "Tests.Codeunit.al"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app := fileResult.Objects[0].App; app == nil || app.Name != "Sales Tests" {
+		t.Errorf("unexpected app from ScanFile: %+v", app)
+	}
+}
+
+func TestAppInfoLabel(t *testing.T) {
+	tests := []struct {
+		app      AppInfo
+		expected string
+	}{
+		{AppInfo{Name: "Sales", Publisher: "Contoso", Version: "1.0.0.0"}, "Sales (Contoso) 1.0.0.0"},
+		{AppInfo{ID: "1", Name: "Sales"}, "Sales"},
+		{AppInfo{ID: "1", Version: "2.0.0.0"}, "1 2.0.0.0"},
+		{AppInfo{}, "(no app)"},
+	}
+	for _, tt := range tests {
+		if got := tt.app.Label(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestScanDirectoryManifestErrorInWalkOrder(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"a/First.Table.al":  "table 50100 \"First\"\n{\n",
+		"b/app.json":        `{"name": `,
+		"c/Second.Table.al": "table 50101 \"Second\"\n{\n",
+		"d/app.json":        `{"name": `,
+	}
+	testutil.WriteFiles(t, root, files)
+
+	result, err := ScanDirectory(root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		CodeUnclosedBlock + " " + filepath.Join(root, "a", "First.Table.al"),
+		CodeManifestError + " " + filepath.Join(root, "b", "app.json"),
+		CodeUnclosedBlock + " " + filepath.Join(root, "c", "Second.Table.al"),
+		CodeManifestError + " " + filepath.Join(root, "d", "app.json"),
+	}
+	if len(result.Diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %+v", len(expected), result.Diagnostics)
+	}
+	for i, d := range result.Diagnostics {
+		if got := d.Code + " " + d.File; got != expected[i] {
+			t.Errorf("diagnostic %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+}
+
+func TestFormatRanges(t *testing.T) {
+	ranges := []IDRange{{From: 50100, To: 50149}, {From: 70000, To: 70099}}
+	if got := FormatRanges(ranges); got != "50100..50149, 70000..70099" {
+		t.Errorf("unexpected ranges %q", got)
+	}
+	if got := FormatRanges(nil); got != "" {
+		t.Errorf("expected no ranges, got %q", got)
+	}
+}
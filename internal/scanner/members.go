@@ -0,0 +1,271 @@
+package scanner
+
+import (
+	"strings"
+)
+
+// Kinds of members declared inside object bodies.
+const (
+	MemberField     = "field"
+	MemberKey       = "key"
+	MemberProcedure = "procedure"
+	MemberTrigger   = "trigger"
+	MemberControl   = "control"
+	MemberAction    = "action"
+	MemberValue     = "value"
+)
+
+// MemberKinds lists every member kind in display order.
+var MemberKinds = []string{MemberField, MemberKey, MemberProcedure, MemberTrigger, MemberControl, MemberAction, MemberValue}
+
+// memberKeywords maps the keywords that declare a member with an argument
+// list, e.g. field(1; "No."; Code[20]), to the member kind. Page fields are
+// reclassified as controls because their first argument is not a field ID.
+var memberKeywords = map[string]string{
+	"field":            MemberField,
+	"key":              MemberKey,
+	"value":            MemberValue,
+	"part":             MemberControl,
+	"systempart":       MemberControl,
+	"usercontrol":      MemberControl,
+	"label":            MemberControl,
+	"action":           MemberAction,
+	"customaction":     MemberAction,
+	"fileuploadaction": MemberAction,
+}
+
+// Member is a field, key, procedure, trigger, page control or action, or enum
+// value declared inside an object.
+//
+// For table fields and enum values ID is the field or value number; DataType
+// is only set for table fields. Access is set for procedures and is one of
+// public, local, internal or protected. Complexity and Nesting are set for
+// procedures and triggers with a body.
+type Member struct {
+	Kind       string    `json:"kind"`
+	ID         string    `json:"id,omitempty"`
+	Name       string    `json:"name"`
+	DataType   string    `json:"dataType,omitempty"`
+	Access     string    `json:"access,omitempty"`
+	Line       int       `json:"line"`
+	Column     int       `json:"column"`
+	Complexity int       `json:"complexity,omitempty"`
+	Nesting    int       `json:"nesting,omitempty"`
+	Obsolete   *Obsolete `json:"obsolete,omitempty"`
+}
+
+// CountMembers returns the number of members of the given kind.
+func (obj *BCObject) CountMembers(kind string) int {
+	count := 0
+	for _, m := range obj.Members {
+		if m.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// parseMembers enumerates the members declared in the tokens of an object
+// body, excluding its braces. Obsolete markings come from the member's
+// property block or, for procedures, from an [Obsolete] attribute.
+func parseMembers(body []Token) []Member {
+	var members []Member
+	var attribute *Obsolete // [Obsolete] seen before the next procedure
+
+	for i := 0; i < len(body); i++ {
+		tok := body[i]
+		if !isMemberStart(body, i) {
+			continue
+		}
+
+		switch {
+		case tok.IsPunct("["):
+			obsolete, next := parseObsoleteAttribute(body, i)
+			if obsolete != nil {
+				attribute = obsolete
+			}
+			i = next - 1
+
+		case tok.IsKeyword("procedure") || tok.IsKeyword("trigger"):
+			if i+1 >= len(body) || !isName(body[i+1]) {
+				continue
+			}
+			member := Member{
+				Kind:   MemberTrigger,
+				Name:   body[i+1].Value,
+				Line:   tok.Line,
+				Column: tok.Column,
+			}
+			if tok.IsKeyword("procedure") {
+				member.Kind = MemberProcedure
+				member.Access = "public"
+				if i > 0 && isAccessModifier(body[i-1]) {
+					member.Access = strings.ToLower(body[i-1].Text)
+				}
+				member.Obsolete = attribute
+			}
+			member.Complexity, member.Nesting = measureCode(body, i+1)
+			attribute = nil
+			members = append(members, member)
+			i++
+
+		case tok.Kind == TokenIdent && i+1 < len(body) && body[i+1].IsPunct("("):
+			kind, ok := memberKeywords[strings.ToLower(tok.Text)]
+			if !ok {
+				continue
+			}
+			member, next := parseArgumentMember(body, i, kind)
+			if member != nil {
+				members = append(members, *member)
+			}
+			i = next - 1
+		}
+	}
+
+	return members
+}
+
+// isMemberStart reports whether body[i] may start a member declaration, i.e.
+// it is the first token of the body or follows the end of a previous
+// declaration, an attribute or an access modifier.
+func isMemberStart(body []Token, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := body[i-1]
+	return prev.IsPunct("{") || prev.IsPunct("}") || prev.IsPunct(";") || prev.IsPunct("]") ||
+		isAccessModifier(prev)
+}
+
+// isAccessModifier reports whether tok is a procedure access modifier.
+func isAccessModifier(tok Token) bool {
+	return tok.IsKeyword("local") || tok.IsKeyword("internal") || tok.IsKeyword("protected")
+}
+
+// parseObsoleteAttribute parses an attribute such as
+// [Obsolete('Use X instead', '24.0')] starting at the opening bracket. It
+// returns the marking, or nil for other attributes, and the index after the
+// closing bracket.
+func parseObsoleteAttribute(body []Token, i int) (*Obsolete, int) {
+	end := i + 1
+	for end < len(body) && !body[end].IsPunct("]") {
+		end++
+	}
+	next := end + 1
+	if i+1 >= end || !body[i+1].IsKeyword("Obsolete") {
+		return nil, next
+	}
+
+	obsolete := &Obsolete{State: ObsoleteStatePending}
+	var args []string
+	for _, tok := range body[i+2 : end] {
+		if tok.Kind == TokenString {
+			args = append(args, tok.Value)
+		}
+	}
+	if len(args) > 0 {
+		obsolete.Reason = args[0]
+	}
+	if len(args) > 1 {
+		obsolete.Tag = args[1]
+	}
+	return obsolete, next
+}
+
+// parseArgumentMember parses a member declared as keyword(arguments) followed
+// by its property block, starting at the keyword:
+//
+//	field(1; "No."; Code[20])     table field
+//	field("No."; Rec."No.")       page control
+//	key(PK; "No.")                key
+//	value(0; "None")              enum value
+//	action(Post)                  page action
+//
+// Requiring the block keeps calls in code, such as a procedure named Value,
+// from being taken for members. It returns the member, or nil if the
+// arguments do not fit the kind, and the index after the argument list.
+func parseArgumentMember(body []Token, i int, kind string) (*Member, int) {
+	args, next := splitArguments(body, i+1)
+	if args == nil || len(args[0]) == 0 || next >= len(body) || !body[next].IsPunct("{") {
+		return nil, next
+	}
+
+	member := &Member{Kind: kind, Line: body[i].Line, Column: body[i].Column}
+	numbered := len(args[0]) == 1 && isObjectID(args[0][0])
+
+	switch {
+	case kind == MemberField && !numbered:
+		member.Kind = MemberControl
+		member.Name = propertyText(args[0])
+	case kind == MemberField || kind == MemberValue:
+		if !numbered || len(args) < 2 || len(args[1]) != 1 || !isName(args[1][0]) {
+			return nil, next
+		}
+		member.ID = args[0][0].Text
+		member.Name = args[1][0].Value
+		if kind == MemberField && len(args) > 2 {
+			member.DataType = propertyText(args[2])
+		}
+	default:
+		member.Name = propertyText(args[0])
+	}
+
+	member.Obsolete = obsoleteFromProperties(blockProperties(body, next))
+	return member, next
+}
+
+// splitArguments splits the parenthesized, semicolon-separated argument list
+// opening at body[open]. It returns the arguments and the index after the
+// closing parenthesis, or nil if body[open] does not open a list.
+func splitArguments(body []Token, open int) ([][]Token, int) {
+	if open >= len(body) || !body[open].IsPunct("(") {
+		return nil, open
+	}
+
+	var args [][]Token
+	start := open + 1
+	depth := 0
+	for j := open; j < len(body); j++ {
+		tok := body[j]
+		switch {
+		case tok.IsPunct("(") || tok.IsPunct("["):
+			depth++
+		case tok.IsPunct(")") || tok.IsPunct("]"):
+			depth--
+			if depth == 0 {
+				return append(args, body[start:j]), j + 1
+			}
+		case tok.IsPunct(";") && depth == 1:
+			args = append(args, body[start:j])
+			start = j + 1
+		case tok.IsPunct("{") || tok.IsPunct("}"):
+			return nil, j
+		}
+	}
+	return nil, len(body)
+}
+
+// blockProperties collects the property assignments directly inside the
+// block opened by the brace at body[open].
+func blockProperties(body []Token, open int) Properties {
+	props := make(Properties)
+	depth := 0
+	for j := open; j < len(body); j++ {
+		tok := body[j]
+		switch {
+		case tok.IsPunct("{"):
+			depth++
+		case tok.IsPunct("}"):
+			depth--
+			if depth == 0 {
+				return props
+			}
+		case depth == 1 && isPropertyStart(body, j):
+			if value, end := parsePropertyValue(body, j+2); end < len(body) && body[end].IsPunct(";") {
+				props.set(tok.Text, value)
+				j = end
+			}
+		}
+	}
+	return props
+}
@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestScanFileMembers(t *testing.T) {
+	content := `table 50100 "Sales Setup"
+{
+    fields
+    {
+        field(1; "Primary Key"; Code[10]) { }
+        field(2; Status; Enum "Sales Status")
+        {
+            trigger OnValidate()
+            begin
+                Validate(Status);
+            end;
+        }
+    }
+
+    keys
+    {
+        key(PK; "Primary Key") { Clustered = true; }
+    }
+
+    trigger OnInsert()
+    begin
+    end;
+
+    procedure GetSetup(): Record "Sales Setup"
+    begin
+    end;
+
+    [EventSubscriber(ObjectType::Codeunit, Codeunit::"Sales-Post", OnBeforePostSalesDoc, '', false, false)]
+    local procedure HandleEvent()
+    begin
+    end;
+
+    internal procedure Value(Key: Integer)
+    begin
+        Value(Key);
+    end;
+}
+
+page 50101 "Sales Setup"
+{
+    layout
+    {
+        area(Content)
+        {
+            group(General)
+            {
+                field("Primary Key"; Rec."Primary Key") { }
+                part(Lines; "Sales Lines") { }
+            }
+        }
+    }
+
+    actions
+    {
+        area(Processing)
+        {
+            action(Post)
+            {
+                trigger OnAction()
+                begin
+                end;
+            }
+        }
+    }
+}
+
+enum 50102 "Sales Status"
+{
+    value(0; Open) { }
+    value(1; "On Hold") { }
+}
+
+interface "ISales"
+{
+    procedure Post();
+    procedure Release(var Header: Record "Sales Header");
+}
+`
+	result := scanSource([]byte(content), "test.al", nil)
+	if len(result.Objects) != 4 {
+		t.Fatalf("expected 4 objects, got %d", len(result.Objects))
+	}
+
+	type member struct {
+		kind, id, name, dataType, access string
+	}
+	tests := []struct {
+		object   string
+		expected []member
+	}{
+		{
+			object: "table",
+			expected: []member{
+				{MemberField, "1", "Primary Key", "Code[10]", ""},
+				{MemberField, "2", "Status", `Enum "Sales Status"`, ""},
+				{MemberTrigger, "", "OnValidate", "", ""},
+				{MemberKey, "", "PK", "", ""},
+				{MemberTrigger, "", "OnInsert", "", ""},
+				{MemberProcedure, "", "GetSetup", "", "public"},
+				{MemberProcedure, "", "HandleEvent", "", "local"},
+				{MemberProcedure, "", "Value", "", "internal"},
+			},
+		},
+		{
+			object: "page",
+			expected: []member{
+				{MemberControl, "", "Primary Key", "", ""},
+				{MemberControl, "", "Lines", "", ""},
+				{MemberAction, "", "Post", "", ""},
+				{MemberTrigger, "", "OnAction", "", ""},
+			},
+		},
+		{
+			object: "enum",
+			expected: []member{
+				{MemberValue, "0", "Open", "", ""},
+				{MemberValue, "1", "On Hold", "", ""},
+			},
+		},
+		{
+			object: "interface",
+			expected: []member{
+				{MemberProcedure, "", "Post", "", "public"},
+				{MemberProcedure, "", "Release", "", "public"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.object, func(t *testing.T) {
+			members := result.Objects[i].Members
+			if len(members) != len(tt.expected) {
+				t.Fatalf("expected %d members, got %d: %+v", len(tt.expected), len(members), members)
+			}
+			for j, exp := range tt.expected {
+				got := member{members[j].Kind, members[j].ID, members[j].Name, members[j].DataType, members[j].Access}
+				if got != exp {
+					t.Errorf("member %d: expected %+v, got %+v", j, exp, got)
+				}
+			}
+		})
+	}
+
+	if n := result.Objects[0].CountMembers(MemberProcedure); n != 3 {
+		t.Errorf("expected 3 procedures, got %d", n)
+	}
+	if m := result.Objects[0].Members[0]; m.Line != 5 || m.Column != 9 {
+		t.Errorf("expected first field at 5:9, got %d:%d", m.Line, m.Column)
+	}
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"bytes"
+	"strings"
+)
+
+// LineCounts classifies source lines. Every physical line is exactly one of
+// code, comment or blank: a line holding both code and a comment counts as
+// code, and lines inside a multi-line /* */ comment count as comment lines.
+// Preprocessor directives and the lines of inactive #if branches are not
+// compiled, so they count as comment lines too.
+type LineCounts struct {
+	Physical int `json:"physical"`
+	Code     int `json:"code"`
+	Comment  int `json:"comment"`
+	Blank    int `json:"blank"`
+}
+
+// Add adds other to the counts.
+func (c *LineCounts) Add(other LineCounts) {
+	c.Physical += other.Physical
+	c.Code += other.Code
+	c.Comment += other.Comment
+	c.Blank += other.Blank
+}
+
+// FileMetrics holds the line counts of a scanned source file.
+type FileMetrics struct {
+	Path  string     `json:"path"`
+	Lines LineCounts `json:"lines"`
+}
+
+// lineKind is the classification of a single source line.
+type lineKind uint8
+
+const (
+	lineBlank lineKind = iota
+	lineComment
+	lineCode
+)
+
+// classifyLines returns the kind of every line of an AL source file, indexed
+// by line number - 1, using its tokens (comments included) to tell code from
+// comments. Directives and the tokens in inactive, which were dropped by the
+// preprocessor, are classified like comments.
+func classifyLines(src []byte, tokens, inactive []Token) []lineKind {
+	kinds := make([]lineKind, physicalLines(src))
+	mark := func(line int, kind lineKind) {
+		if line >= 1 && line <= len(kinds) && kinds[line-1] < kind {
+			kinds[line-1] = kind
+		}
+	}
+
+	dropped := make(map[int]bool, len(inactive))
+	for _, tok := range inactive {
+		dropped[tok.Offset] = true
+	}
+
+	for _, tok := range tokens {
+		switch {
+		case tok.Kind == TokenEOF:
+			continue
+		case tok.Kind == TokenDirective, dropped[tok.Offset]:
+			mark(tok.Line, lineComment)
+		case tok.Kind == TokenComment:
+			// Block comments may span several lines
+			last := tok.Line + strings.Count(tok.Text, "\n")
+			for line := tok.Line; line <= last; line++ {
+				mark(line, lineComment)
+			}
+		default:
+			mark(tok.Line, lineCode)
+		}
+	}
+	return kinds
+}
+
+// classifyTextLines classifies the lines of a C/AL text export, where only
+// whole-line // comments are recognized.
+func classifyTextLines(src []byte) []lineKind {
+	lines := bytes.Split(src, []byte("\n"))
+	kinds := make([]lineKind, physicalLines(src))
+	for i := range kinds {
+		line := bytes.TrimSpace(lines[i])
+		switch {
+		case len(line) == 0:
+			kinds[i] = lineBlank
+		case bytes.HasPrefix(line, []byte("//")):
+			kinds[i] = lineComment
+		default:
+			kinds[i] = lineCode
+		}
+	}
+	return kinds
+}
+
+// physicalLines returns the number of lines in src. A final line without a
+// trailing newline counts as a line; an empty file has none.
+func physicalLines(src []byte) int {
+	n := bytes.Count(src, []byte("\n"))
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// countLines sums the classified lines from first to last (1-based, inclusive).
+func countLines(kinds []lineKind, first, last int) LineCounts {
+	var counts LineCounts
+	if first < 1 {
+		first = 1
+	}
+	if last > len(kinds) {
+		last = len(kinds)
+	}
+	for line := first; line <= last; line++ {
+		counts.Physical++
+		switch kinds[line-1] {
+		case lineCode:
+			counts.Code++
+		case lineComment:
+			counts.Comment++
+		default:
+			counts.Blank++
+		}
+	}
+	return counts
+}
+
+// applyLineMetrics records the line counts of the file and of each of its
+// objects on result.
+func applyLineMetrics(result *FileResult, path string, kinds []lineKind) {
+	result.Metrics = &FileMetrics{Path: path, Lines: countLines(kinds, 1, len(kinds))}
+	for i := range result.Objects {
+		obj := &result.Objects[i]
+		obj.Lines = countLines(kinds, obj.Line, obj.EndLine)
+	}
+	for i := range result.InactiveObjects {
+		obj := &result.InactiveObjects[i]
+		obj.Lines = countLines(kinds, obj.Line, obj.EndLine)
+	}
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andrijan/bc-objects-counter/internal/testutil"
+)
+
+func TestScanFileLineMetrics(t *testing.T) {
+	content := `// Header comment
+
+/// <summary>
+/// Sales setup.
+/// </summary>
+table 50100 "Sales Setup"
+{
+    /* Block comment
+       spanning lines
+
+    */
+    Caption = 'Sales Setup'; // trailing comment
+
+    fields
+    {
+        field(1; "Primary Key"; Code[10]) { }
+    }
+}
+
+codeunit 50101 "Sales Mgt"
+{
+}`
+	result := scanSource([]byte(content), "test.al", nil)
+
+	expectedFile := LineCounts{Physical: 22, Code: 11, Comment: 8, Blank: 3}
+	if result.Metrics == nil || result.Metrics.Lines != expectedFile {
+		t.Errorf("expected file lines %+v, got %+v", expectedFile, result.Metrics)
+	}
+	if result.Metrics != nil && result.Metrics.Path != "test.al" {
+		t.Errorf("expected path test.al, got %s", result.Metrics.Path)
+	}
+
+	expectedObjects := []LineCounts{
+		{Physical: 13, Code: 8, Comment: 4, Blank: 1},
+		{Physical: 3, Code: 3},
+	}
+	if len(result.Objects) != len(expectedObjects) {
+		t.Fatalf("expected %d objects, got %d", len(expectedObjects), len(result.Objects))
+	}
+	for i, exp := range expectedObjects {
+		if result.Objects[i].Lines != exp {
+			t.Errorf("object %d: expected %+v, got %+v", i, exp, result.Objects[i].Lines)
+		}
+	}
+}
+
+func TestScanFileLineMetricsPreprocessor(t *testing.T) {
+	content := `codeunit 50100 "Shipping"
+{
+#if CLEAN24
+    procedure Ship()
+    begin
+    end;
+#else
+    procedure ShipLegacy()
+    begin
+    end;
+#endif
+}
+`
+	tests := []struct {
+		name    string
+		symbols SymbolSet
+	}{
+		{name: "else branch", symbols: nil},
+		{name: "if branch", symbols: NewSymbolSet("CLEAN24")},
+	}
+
+	// Either way three procedure lines are compiled; the directives and the
+	// other branch are not
+	expected := LineCounts{Physical: 12, Code: 6, Comment: 6}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := scanSource([]byte(content), "test.al", tt.symbols)
+			if result.Metrics == nil || result.Metrics.Lines != expected {
+				t.Errorf("expected file lines %+v, got %+v", expected, result.Metrics)
+			}
+			if len(result.Objects) != 1 || result.Objects[0].Lines != expected {
+				t.Errorf("expected object lines %+v, got %+v", expected, result.Objects)
+			}
+		})
+	}
+}
+
+func TestPhysicalLines(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"a\n", 1},
+		{"a\r\nb", 2},
+		{"\n\n", 2},
+	}
+	for _, tt := range tests {
+		if n := physicalLines([]byte(tt.src)); n != tt.expected {
+			t.Errorf("%q: expected %d, got %d", tt.src, tt.expected, n)
+		}
+	}
+}
+
+func TestScanDirectoryFileMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"a.al":      "table 50100 A\n{\n}\n",
+		"notes.txt": "just some notes\n",
+	}
+	testutil.WriteFiles(t, tmpDir, files)
+
+	result, err := ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, IncludeCAL: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Plain text files are not source code, even when C/AL exports are read
+	if len(result.Files) != 1 || result.Files[0].Lines.Code != 3 {
+		t.Errorf("expected metrics for a.al only, got %+v", result.Files)
+	}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"strings"
+)
+
+// Obsolete describes an object or member marked with ObsoleteState (or the
+// [Obsolete] attribute on procedures), together with its ObsoleteReason and
+// ObsoleteTag.
+type Obsolete struct {
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// Obsolete states used by AL.
+const (
+	ObsoleteStatePending = "Pending"
+	ObsoleteStateRemoved = "Removed"
+)
+
+// ObsoleteMember is a table field, enum value or procedure marked obsolete
+// inside an object.
+type ObsoleteMember struct {
+	Kind     string   `json:"kind"`
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Obsolete Obsolete `json:"obsolete"`
+}
+
+// obsoleteKinds are the member kinds whose obsolete markings are reported.
+// Keys, triggers, page controls and actions are left out.
+var obsoleteKinds = map[string]bool{
+	MemberField:     true,
+	MemberValue:     true,
+	MemberProcedure: true,
+}
+
+// obsoleteMembers returns the table fields, enum values and procedures among
+// members that are marked obsolete.
+func obsoleteMembers(members []Member) []ObsoleteMember {
+	var result []ObsoleteMember
+	for _, m := range members {
+		if m.Obsolete == nil || !obsoleteKinds[m.Kind] {
+			continue
+		}
+		result = append(result, ObsoleteMember{
+			Kind:     m.Kind,
+			ID:       m.ID,
+			Name:     m.Name,
+			Line:     m.Line,
+			Column:   m.Column,
+			Obsolete: *m.Obsolete,
+		})
+	}
+	return result
+}
+
+// obsoleteFromProperties returns the obsolete marking described by props, or
+// nil if ObsoleteState is not set or is No.
+func obsoleteFromProperties(props Properties) *Obsolete {
+	state := props.Get("ObsoleteState")
+	if state == "" || strings.EqualFold(state, "No") {
+		return nil
+	}
+	return &Obsolete{
+		State:  state,
+		Reason: props.Get("ObsoleteReason"),
+		Tag:    props.Get("ObsoleteTag"),
+	}
+}
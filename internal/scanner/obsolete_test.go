@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestScanFileObsolete(t *testing.T) {
+	content := `table 50100 "Old Setup"
+{
+    ObsoleteState = Pending;
+    ObsoleteReason = 'Replaced by Setup v2';
+    ObsoleteTag = '24.0';
+
+    fields
+    {
+        field(1; "Primary Key"; Code[10]) { }
+        field(2; "Legacy Code"; Code[20])
+        {
+            Caption = 'Legacy Code';
+            ObsoleteState = Removed;
+            ObsoleteReason = 'Not used';
+            ObsoleteTag = '23.0';
+        }
+        field(3; Active; Boolean)
+        {
+            ObsoleteState = No;
+        }
+    }
+
+    [Obsolete('Use NewProc instead', '25.0')]
+    [Scope('OnPrem')]
+    procedure OldProc()
+    begin
+    end;
+
+    [Obsolete]
+    local procedure "Older Proc"()
+    begin
+    end;
+
+    procedure NewProc()
+    var
+        Field: Record Field;
+    begin
+        if Field.Get(1, 2) then;
+    end;
+}
+
+enum 50101 "Status"
+{
+    value(0; Open) { }
+    value(1; "On Hold")
+    {
+        ObsoleteState = Pending;
+        ObsoleteTag = '24.0';
+    }
+}
+
+page 50102 "Setup"
+{
+    layout
+    {
+        area(Content)
+        {
+            field("Primary Key"; Rec."Primary Key")
+            {
+                ObsoleteState = Pending;
+            }
+        }
+    }
+}
+`
+	result := scanSource([]byte(content), "test.al", nil)
+	if len(result.Objects) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(result.Objects))
+	}
+
+	table := result.Objects[0]
+	if table.Obsolete == nil || *table.Obsolete != (Obsolete{State: "Pending", Reason: "Replaced by Setup v2", Tag: "24.0"}) {
+		t.Errorf("unexpected table obsolete marking %+v", table.Obsolete)
+	}
+
+	expected := []ObsoleteMember{
+		{Kind: MemberField, ID: "2", Name: "Legacy Code", Line: 10, Column: 9,
+			Obsolete: Obsolete{State: "Removed", Reason: "Not used", Tag: "23.0"}},
+		{Kind: MemberProcedure, Name: "OldProc", Line: 25, Column: 5,
+			Obsolete: Obsolete{State: "Pending", Reason: "Use NewProc instead", Tag: "25.0"}},
+		{Kind: MemberProcedure, Name: "Older Proc", Line: 30, Column: 11,
+			Obsolete: Obsolete{State: "Pending"}},
+	}
+	if len(table.ObsoleteMembers) != len(expected) {
+		t.Fatalf("expected %d obsolete members, got %d: %+v", len(expected), len(table.ObsoleteMembers), table.ObsoleteMembers)
+	}
+	for i, exp := range expected {
+		if table.ObsoleteMembers[i] != exp {
+			t.Errorf("member %d: expected %+v, got %+v", i, exp, table.ObsoleteMembers[i])
+		}
+	}
+
+	enum := result.Objects[1]
+	if enum.Obsolete != nil {
+		t.Errorf("expected enum not to be obsolete, got %+v", enum.Obsolete)
+	}
+	if len(enum.ObsoleteMembers) != 1 || enum.ObsoleteMembers[0].Kind != MemberValue || enum.ObsoleteMembers[0].Name != "On Hold" {
+		t.Errorf("expected obsolete value On Hold, got %+v", enum.ObsoleteMembers)
+	}
+
+	if page := result.Objects[2]; len(page.ObsoleteMembers) != 0 {
+		t.Errorf("expected page fields to be ignored, got %+v", page.ObsoleteMembers)
+	}
+}
@@ -0,0 +1,281 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// parseObjects walks a token stream and extracts the object declarations
+// found at the top level, i.e. outside of any { } block. Tracking brace
+// depth is what keeps references such as `table "Customer" = X` inside a
+// permission set from being counted as objects.
+//
+// Top-level namespace declarations and using directives apply to every
+// object that follows them in the file.
+//
+// Each object records where its declaration starts and where the closing
+// brace of its body ends, so callers can slice the object's source text.
+// Property assignments directly inside the body are collected into the
+// object's Properties, and the members declared in the body (fields,
+// procedures, ...) into its Members, with the obsolete ones among them in
+// ObsoleteMembers.
+// An object whose body is never closed is reported as a diagnostic.
+func parseObjects(tokens []Token, filePath string) ([]BCObject, []Diagnostic) {
+	toks := significantTokens(tokens)
+
+	var objects []BCObject
+	var namespace string
+	var usings []string
+	depth := 0
+	pending := -1 // index of the object whose body has not been closed yet
+	inBody := false
+	bodyStart := 0 // index of the opening brace of the pending object's body
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		switch {
+		case tok.IsPunct("{"):
+			if depth == 0 && pending >= 0 {
+				inBody = true
+				bodyStart = i
+			}
+			depth++
+		case tok.IsPunct("}"):
+			if depth > 0 {
+				depth--
+			}
+			if depth == 0 && inBody {
+				setObjectEnd(&objects[pending], tok)
+				objects[pending].Members = parseMembers(toks[bodyStart+1 : i])
+				pending = -1
+				inBody = false
+			}
+		case depth == 1 && inBody && isPropertyStart(toks, i):
+			if value, end := parsePropertyValue(toks, i+2); end < len(toks) && toks[end].IsPunct(";") {
+				obj := &objects[pending]
+				if obj.Properties == nil {
+					obj.Properties = make(Properties)
+				}
+				obj.Properties.set(tok.Text, value)
+				i = end - 1
+			}
+		case depth == 0 && tok.IsKeyword("namespace"):
+			if name, next := parseDottedName(toks, i+1); name != "" {
+				namespace = name
+				i = next - 1
+			}
+		case depth == 0 && tok.IsKeyword("using"):
+			if name, next := parseDottedName(toks, i+1); name != "" {
+				usings = append(usings, name)
+				i = next - 1
+			}
+		case depth == 0 && tok.Kind == TokenIdent:
+			if obj, next := parseObjectHeader(toks, i, filePath); obj != nil {
+				obj.Namespace = namespace
+				obj.QualifiedName = qualifiedName(namespace, obj.Name)
+				if len(usings) > 0 {
+					obj.Usings = append([]string(nil), usings...)
+				}
+				setObjectEnd(obj, toks[next-1])
+				objects = append(objects, *obj)
+				pending = len(objects) - 1
+				inBody = false
+				i = next - 1
+			}
+		}
+	}
+
+	var diagnostics []Diagnostic
+	if inBody {
+		obj := objects[pending]
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     obj.Line,
+			Column:   obj.Column,
+			Severity: SeverityWarning,
+			Code:     CodeUnclosedBlock,
+			Message:  fmt.Sprintf("%s %q has no closing brace", obj.Type, obj.Name),
+		})
+		if len(toks) > 0 {
+			setObjectEnd(&objects[pending], toks[len(toks)-1])
+		}
+		objects[pending].Members = parseMembers(toks[bodyStart+1:])
+	}
+
+	for i := range objects {
+		objects[i].Obsolete = obsoleteFromProperties(objects[i].Properties)
+		objects[i].ObsoleteMembers = obsoleteMembers(objects[i].Members)
+	}
+
+	return objects, diagnostics
+}
+
+// setObjectEnd records tok as the last token belonging to obj.
+func setObjectEnd(obj *BCObject, tok Token) {
+	obj.EndLine = tok.Line
+	obj.EndOffset = tok.Offset + len(tok.Text)
+}
+
+// parseDottedName parses a namespace name such as Contoso.Sales starting at
+// toks[i], followed by a terminating semicolon. It returns the name and the
+// index after the semicolon, or "" if the tokens do not form such a name.
+func parseDottedName(toks []Token, i int) (string, int) {
+	parts, i := parseNameParts(toks, i)
+	if len(parts) == 0 || i >= len(toks) || !toks[i].IsPunct(";") {
+		return "", i
+	}
+	return strings.Join(parts, "."), i + 1
+}
+
+// parseNameParts parses the dot-separated names starting at toks[i], e.g.
+// Microsoft.Sales."Sales Header". It returns their values and the index of
+// the first token after them.
+func parseNameParts(toks []Token, i int) ([]string, int) {
+	var parts []string
+	for i < len(toks) && isName(toks[i]) {
+		parts = append(parts, toks[i].Value)
+		i++
+		if i+1 < len(toks) && toks[i].IsPunct(".") && isName(toks[i+1]) {
+			i++
+			continue
+		}
+		break
+	}
+	return parts, i
+}
+
+// parseTargetName parses the object referenced by an extends or implements
+// clause, which may be qualified by its namespace. Qualified targets are
+// returned in the form of qualifiedName; an unqualified target is its plain
+// name, quoted only if it contains a dot, so that UnqualifiedName can take it
+// apart again. It returns "" if toks[i] is not a name.
+func parseTargetName(toks []Token, i int) (string, int) {
+	parts, next := parseNameParts(toks, i)
+	switch {
+	case len(parts) == 0:
+		return "", i
+	case len(parts) > 1:
+		return qualifiedName(strings.Join(parts[:len(parts)-1], "."), parts[len(parts)-1]), next
+	case strings.Contains(parts[0], "."):
+		return `"` + parts[0] + `"`, next
+	}
+	return parts[0], next
+}
+
+// UnqualifiedName returns the object name of a possibly namespace-qualified
+// reference such as an extends target: "Sales Header" for
+// Microsoft.Sales."Sales Header", and Customer for Customer.
+func UnqualifiedName(name string) string {
+	if strings.HasSuffix(name, `"`) {
+		if start := strings.LastIndex(name[:len(name)-1], `"`); start >= 0 {
+			return name[start+1 : len(name)-1]
+		}
+	}
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		return name[dot+1:]
+	}
+	return name
+}
+
+// qualifiedName combines a namespace and an object name into the fully
+// qualified form used by AL, e.g. Contoso.Sales."Sales Header".
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + quoteIfNeeded(name)
+}
+
+// quoteIfNeeded wraps name in double quotes unless it is a plain identifier.
+func quoteIfNeeded(name string) string {
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return `"` + name + `"`
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// parseObjectHeader tries to parse an object declaration starting at toks[i].
+// The keyword, ID, name and any extends/implements clauses may be spread
+// over several lines, and names may be quoted or plain identifiers.
+// It returns the object and the index of the first token after the header,
+// or nil if toks[i] does not start a declaration.
+func parseObjectHeader(toks []Token, i int, filePath string) (*BCObject, int) {
+	objType := strings.ToLower(toks[i].Text)
+	j := i + 1
+
+	id := ""
+	switch {
+	case objectTypesWithID[objType]:
+		if j >= len(toks) || !isObjectID(toks[j]) {
+			return nil, i + 1
+		}
+		id = toks[j].Text
+		j++
+	case !objectTypesNoID[objType]:
+		return nil, i + 1
+	}
+
+	if j >= len(toks) || !isName(toks[j]) {
+		return nil, i + 1
+	}
+	obj := &BCObject{
+		Type:     objType,
+		ID:       id,
+		Name:     toks[j].Value,
+		FilePath: filePath,
+		Line:     toks[i].Line,
+		Column:   toks[i].Column,
+		Offset:   toks[i].Offset,
+	}
+	j++
+
+	// Optional clauses: extends <target> and implements <interface>, ...
+	for j < len(toks) {
+		switch {
+		case toks[j].IsKeyword("extends") && j+1 < len(toks) && isName(toks[j+1]):
+			obj.Extends, j = parseTargetName(toks, j+1)
+		case toks[j].IsKeyword("implements") && j+1 < len(toks) && isName(toks[j+1]):
+			var iface string
+			iface, j = parseTargetName(toks, j+1)
+			obj.Implements = append(obj.Implements, iface)
+			for j+1 < len(toks) && toks[j].IsPunct(",") && isName(toks[j+1]) {
+				iface, j = parseTargetName(toks, j+1)
+				obj.Implements = append(obj.Implements, iface)
+			}
+		default:
+			return obj, j
+		}
+	}
+
+	return obj, j
+}
+
+// isName reports whether tok can be used as an object name: a quoted or plain identifier.
+func isName(tok Token) bool {
+	return tok.Kind == TokenQuotedIdent || tok.Kind == TokenIdent
+}
+
+// isObjectID reports whether tok is an integer literal usable as an object ID.
+func isObjectID(tok Token) bool {
+	return tok.Kind == TokenNumber && !strings.Contains(tok.Text, ".")
+}
+
+// significantTokens filters out comments, directives and the EOF marker,
+// leaving only the tokens that carry meaning for object detection.
+func significantTokens(tokens []Token) []Token {
+	result := make([]Token, 0, len(tokens))
+	for _, tok := range tokens {
+		switch tok.Kind {
+		case TokenComment, TokenDirective, TokenEOF:
+			continue
+		}
+		result = append(result, tok)
+	}
+	return result
+}
@@ -0,0 +1,324 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SymbolSet is a set of preprocessor symbols. Symbols are matched
+// case-insensitively, like AL identifiers.
+type SymbolSet map[string]bool
+
+// NewSymbolSet creates a symbol set from a list of symbol names.
+func NewSymbolSet(symbols ...string) SymbolSet {
+	set := make(SymbolSet, len(symbols))
+	for _, s := range symbols {
+		if s = strings.TrimSpace(s); s != "" {
+			set[strings.ToLower(s)] = true
+		}
+	}
+	return set
+}
+
+// Has reports whether symbol is defined.
+func (s SymbolSet) Has(symbol string) bool {
+	return s[strings.ToLower(symbol)]
+}
+
+// Sorted returns the symbols in sorted order.
+func (s SymbolSet) Sorted() []string {
+	symbols := make([]string, 0, len(s))
+	for symbol := range s {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// conditional is one level of #if nesting.
+type conditional struct {
+	parentActive bool // whether the enclosing code is active
+	taken        bool // whether an earlier branch of this #if was active
+	active       bool // whether the current branch is active
+	sawElse      bool
+	line, column int
+}
+
+// applyPreprocessor evaluates #if/#elif/#else/#endif and #define/#undef
+// directives against symbols and splits the tokens into those in active and
+// those in inactive conditional branches. Directive tokens are dropped from
+// both. #region, #pragma and other directives are ignored. Malformed
+// directives are reported as diagnostics.
+func applyPreprocessor(tokens []Token, symbols SymbolSet, filePath string) (active, inactive []Token, diagnostics []Diagnostic) {
+	// #define and #undef only affect the current file
+	defined := make(SymbolSet, len(symbols))
+	for s := range symbols {
+		defined[s] = true
+	}
+
+	report := func(tok Token, msg string) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     tok.Line,
+			Column:   tok.Column,
+			Severity: SeverityWarning,
+			Code:     CodePreprocessor,
+			Message:  msg,
+		})
+	}
+
+	var stack []conditional
+	isActive := func() bool {
+		return len(stack) == 0 || stack[len(stack)-1].active
+	}
+
+	for _, tok := range tokens {
+		if tok.Kind != TokenDirective {
+			if isActive() || tok.Kind == TokenEOF {
+				active = append(active, tok)
+			} else {
+				inactive = append(inactive, tok)
+			}
+			continue
+		}
+
+		name, expr := splitDirective(tok.Text)
+		switch name {
+		case "if":
+			value, err := evalCondition(expr, defined)
+			if err != nil {
+				report(tok, err.Error())
+			}
+			parent := isActive()
+			stack = append(stack, conditional{
+				parentActive: parent,
+				taken:        parent && value,
+				active:       parent && value,
+				line:         tok.Line,
+				column:       tok.Column,
+			})
+
+		case "elif":
+			if len(stack) == 0 {
+				report(tok, "#elif without #if")
+				continue
+			}
+			top := &stack[len(stack)-1]
+			if top.sawElse {
+				report(tok, "#elif after #else")
+			}
+			value, err := evalCondition(expr, defined)
+			if err != nil {
+				report(tok, err.Error())
+			}
+			top.active = top.parentActive && !top.taken && value
+			top.taken = top.taken || top.active
+
+		case "else":
+			if len(stack) == 0 {
+				report(tok, "#else without #if")
+				continue
+			}
+			top := &stack[len(stack)-1]
+			if top.sawElse {
+				report(tok, "duplicate #else")
+			}
+			top.sawElse = true
+			top.active = top.parentActive && !top.taken
+			top.taken = true
+
+		case "endif":
+			if len(stack) == 0 {
+				report(tok, "#endif without #if")
+				continue
+			}
+			stack = stack[:len(stack)-1]
+
+		case "define", "undef":
+			if !isActive() {
+				continue
+			}
+			symbol := strings.TrimSpace(expr)
+			if symbol == "" {
+				report(tok, fmt.Sprintf("#%s without a symbol", name))
+				continue
+			}
+			if name == "define" {
+				defined[strings.ToLower(symbol)] = true
+			} else {
+				delete(defined, strings.ToLower(symbol))
+			}
+		}
+	}
+
+	for _, c := range stack {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     c.line,
+			Column:   c.column,
+			Severity: SeverityWarning,
+			Code:     CodePreprocessor,
+			Message:  "#if without matching #endif",
+		})
+	}
+
+	return active, inactive, diagnostics
+}
+
+// topLevelInactive returns the inactive tokens that lie outside any braces of
+// the active code around them. Inactive branches inside an object body are
+// fragments of that body, such as a variable of type Interface "INewShipper",
+// and must not be parsed as object declarations on their own. Both slices are
+// in source order.
+func topLevelInactive(active, inactive []Token) []Token {
+	var result []Token
+	depth := 0
+	next := 0 // first active token not yet counted
+	for _, tok := range inactive {
+		for ; next < len(active) && active[next].Offset < tok.Offset; next++ {
+			switch {
+			case active[next].IsPunct("{"):
+				depth++
+			case active[next].IsPunct("}") && depth > 0:
+				depth--
+			}
+		}
+		if depth == 0 {
+			result = append(result, tok)
+		}
+	}
+	return result
+}
+
+// splitDirective splits a directive line such as "#if not CLEAN24 // note"
+// into its lowercased name and the remaining expression, without comments.
+func splitDirective(text string) (string, string) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "#")
+	if i := strings.Index(text, "//"); i >= 0 {
+		text = text[:i]
+	}
+	text = strings.TrimSpace(text)
+	name, rest := text, ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		name, rest = text[:i], text[i:]
+	}
+	return strings.ToLower(name), strings.TrimSpace(rest)
+}
+
+// evalCondition evaluates a preprocessor condition built from symbols,
+// not/and/or and parentheses. Invalid conditions evaluate to false.
+func evalCondition(expr string, symbols SymbolSet) (bool, error) {
+	p := &conditionParser{tokens: significantTokens(Tokenize([]byte(expr))), symbols: symbols}
+	if len(p.tokens) == 0 {
+		return false, fmt.Errorf("missing condition")
+	}
+
+	value, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("unexpected %q in condition %q", p.tokens[p.pos].Text, expr)
+	}
+	return value, nil
+}
+
+// conditionParser is a recursive descent parser for preprocessor conditions:
+//
+//	or    = and { "or" and }
+//	and   = unary { "and" unary }
+//	unary = "not" unary | "(" or ")" | symbol
+type conditionParser struct {
+	tokens  []Token
+	pos     int
+	symbols SymbolSet
+}
+
+func (p *conditionParser) peek() (Token, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return Token{}, false
+}
+
+func (p *conditionParser) parseOr() (bool, error) {
+	value, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || !(tok.IsKeyword("or") || tok.IsPunct("|")) {
+			return value, nil
+		}
+		p.pos++
+		if tok.IsPunct("|") {
+			// Accept C-style || as well
+			if next, ok := p.peek(); ok && next.IsPunct("|") {
+				p.pos++
+			}
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		value = value || right
+	}
+}
+
+func (p *conditionParser) parseAnd() (bool, error) {
+	value, err := p.parseUnary()
+	if err != nil {
+		return false, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || !(tok.IsKeyword("and") || tok.IsPunct("&")) {
+			return value, nil
+		}
+		p.pos++
+		if tok.IsPunct("&") {
+			// Accept C-style && as well
+			if next, ok := p.peek(); ok && next.IsPunct("&") {
+				p.pos++
+			}
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		value = value && right
+	}
+}
+
+func (p *conditionParser) parseUnary() (bool, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return false, fmt.Errorf("unexpected end of condition")
+	}
+
+	switch {
+	case tok.IsKeyword("not") || tok.IsPunct("!"):
+		p.pos++
+		value, err := p.parseUnary()
+		return !value, err
+	case tok.IsPunct("("):
+		p.pos++
+		value, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if next, ok := p.peek(); !ok || !next.IsPunct(")") {
+			return false, fmt.Errorf("missing ) in condition")
+		}
+		p.pos++
+		return value, nil
+	case tok.Kind == TokenIdent:
+		p.pos++
+		return p.symbols.Has(tok.Text), nil
+	}
+
+	return false, fmt.Errorf("unexpected %q in condition", tok.Text)
+}
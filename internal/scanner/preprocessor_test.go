@@ -0,0 +1,282 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEvalCondition(t *testing.T) {
+	symbols := NewSymbolSet("CLEAN24", "onprem")
+
+	tests := []struct {
+		expr     string
+		expected bool
+		wantErr  bool
+	}{
+		{expr: "CLEAN24", expected: true},
+		{expr: "clean24", expected: true},
+		{expr: "CLEAN25", expected: false},
+		{expr: "not CLEAN24", expected: false},
+		{expr: "NOT CLEAN25", expected: true},
+		{expr: "CLEAN24 and ONPREM", expected: true},
+		{expr: "CLEAN24 and CLEAN25", expected: false},
+		{expr: "CLEAN25 or ONPREM", expected: true},
+		{expr: "not (CLEAN25 or CLOUD)", expected: true},
+		{expr: "CLEAN25 or CLEAN24 and ONPREM", expected: true},
+		{expr: "", wantErr: true},
+		{expr: "CLEAN24 and", wantErr: true},
+		{expr: "(CLEAN24", wantErr: true},
+		{expr: "CLEAN24 CLEAN25", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalCondition(tt.expr, symbols)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScanFileConditionalObjects(t *testing.T) {
+	content := `#if CLEAN24
+table 50100 "New Table"
+{
+}
+#elif CLEAN23
+table 50100 "Interim Table"
+{
+}
+#else
+table 50100 "Old Table"
+{
+    fields
+    {
+#if not CLEAN22
+        field(1; "Legacy"; Code[20]) { }
+#endif
+    }
+}
+#endif
+
+#region Helpers
+codeunit 50101 "Always There"
+{
+}
+#endregion
+`
+
+	tests := []struct {
+		name     string
+		symbols  SymbolSet
+		active   []string
+		inactive []string
+	}{
+		{
+			name:     "no symbols",
+			symbols:  nil,
+			active:   []string{"Old Table", "Always There"},
+			inactive: []string{"New Table", "Interim Table"},
+		},
+		{
+			name:     "if branch",
+			symbols:  NewSymbolSet("CLEAN24", "CLEAN23"),
+			active:   []string{"New Table", "Always There"},
+			inactive: []string{"Interim Table", "Old Table"},
+		},
+		{
+			name:     "elif branch",
+			symbols:  NewSymbolSet("CLEAN23"),
+			active:   []string{"Interim Table", "Always There"},
+			inactive: []string{"New Table", "Old Table"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := scanSource([]byte(content), "test.al", tt.symbols)
+			if len(result.Diagnostics) != 0 {
+				t.Errorf("expected no diagnostics, got %v", result.Diagnostics)
+			}
+			assertNames(t, "active", result.Objects, tt.active)
+			assertNames(t, "inactive", result.InactiveObjects, tt.inactive)
+		})
+	}
+}
+
+func TestSplitDirective(t *testing.T) {
+	tests := []struct {
+		text, name, expr string
+	}{
+		{"#if CLEAN24", "if", "CLEAN24"},
+		{"#if\tCLEAN24", "if", "CLEAN24"},
+		{"  #IF  not CLEAN24 // note", "if", "not CLEAN24"},
+		{"#else", "else", ""},
+		{"#endif\t// done", "endif", ""},
+	}
+	for _, tt := range tests {
+		name, expr := splitDirective(tt.text)
+		if name != tt.name || expr != tt.expr {
+			t.Errorf("%q: expected %q %q, got %q %q", tt.text, tt.name, tt.expr, name, expr)
+		}
+	}
+}
+
+func TestScanFileTabSeparatedDirective(t *testing.T) {
+	content := "#if\tCLEAN24\ncodeunit 50100 \"New\"\n{\n}\n#else\ncodeunit 50100 \"Old\"\n{\n}\n#endif\n"
+	result := scanSource([]byte(content), "test.al", NewSymbolSet("CLEAN24"))
+	assertNames(t, "active", result.Objects, []string{"New"})
+	assertNames(t, "inactive", result.InactiveObjects, []string{"Old"})
+}
+
+func TestScanFileDefineUndef(t *testing.T) {
+	content := `#define FEATURE
+#if FEATURE
+codeunit 50100 "Feature"
+{
+}
+#endif
+#undef FEATURE
+#if FEATURE
+codeunit 50101 "Never"
+{
+}
+#endif
+`
+	result := scanSource([]byte(content), "test.al", nil)
+	assertNames(t, "active", result.Objects, []string{"Feature"})
+	assertNames(t, "inactive", result.InactiveObjects, []string{"Never"})
+}
+
+func TestScanFileInactiveBranchInBody(t *testing.T) {
+	content := `codeunit 50100 "Shipping"
+{
+    procedure Ship()
+    var
+#if CLEAN24
+        Shipper: Interface "INewShipper";
+#else
+        Shipper: Interface "IShipper";
+#endif
+    begin
+    end;
+}
+
+#if CLEAN24
+interface "INewShipper"
+{
+}
+#endif
+`
+	result := scanSource([]byte(content), "test.al", nil)
+	assertNames(t, "active", result.Objects, []string{"Shipping"})
+	assertNames(t, "inactive", result.InactiveObjects, []string{"INewShipper"})
+	if got := result.InactiveObjects[0].Line; got != 15 {
+		t.Errorf("expected the inactive interface on line 15, got %d", got)
+	}
+}
+
+func TestScanFilePreprocessorDiagnostics(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{name: "unterminated if", content: "#if X\ntable 50100 A { }\n", line: 1},
+		{name: "endif without if", content: "table 50100 A { }\n#endif\n", line: 2},
+		{name: "else without if", content: "\n#else\n", line: 2},
+		{name: "bad condition", content: "#if X and\n#endif\n", line: 1},
+		{name: "duplicate else", content: "#if X\n#else\n#else\n#endif\n", line: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := scanSource([]byte(tt.content), "test.al", nil)
+			if len(result.Diagnostics) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %v", result.Diagnostics)
+			}
+			d := result.Diagnostics[0]
+			if d.Code != CodePreprocessor || d.Line != tt.line {
+				t.Errorf("expected %s at line %d, got %s at line %d", CodePreprocessor, tt.line, d.Code, d.Line)
+			}
+		})
+	}
+}
+
+func TestScanDirectoryManifestSymbols(t *testing.T) {
+	root := t.TempDir()
+	content := `#if CLEAN24
+codeunit 50100 "Clean"
+{
+}
+#else
+codeunit 50100 "Legacy"
+{
+}
+#endif
+`
+	// app1 defines CLEAN24 in its app.json; app2 does not
+	for _, dir := range []string{"app1/src", "app2/src"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "Codeunit.al"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := "\xef\xbb\xbf" + `{"id": "1", "name": "App 1", "preprocessorSymbols": ["CLEAN24"]}`
+	if err := os.WriteFile(filepath.Join(root, "app1", "app.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "app2", "app.json"), []byte(`{"id": "2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanDirectory(root, true)
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+	assertNames(t, "active", result.Objects, []string{"Clean", "Legacy"})
+	assertNames(t, "inactive", result.InactiveObjects, []string{"Legacy", "Clean"})
+
+	// --define applies to every file
+	result, err = ScanDirectoryContext(context.Background(), root, Options{Recursive: true, Defines: []string{"clean24"}})
+	if err != nil {
+		t.Fatalf("ScanDirectoryContext failed: %v", err)
+	}
+	assertNames(t, "active", result.Objects, []string{"Clean", "Clean"})
+
+	// Scanning a subdirectory still picks up the app.json above it
+	result, err = ScanDirectory(filepath.Join(root, "app1", "src"), true)
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+	assertNames(t, "active", result.Objects, []string{"Clean"})
+}
+
+func TestCacheKeyIncludesSymbols(t *testing.T) {
+	if key := cacheKey("a.al", nil); key != "a.al" {
+		t.Errorf("expected a.al, got %s", key)
+	}
+	if key := cacheKey("a.al", NewSymbolSet("B", "a")); key != "a.al#a;b" {
+		t.Errorf("expected a.al#a;b, got %s", key)
+	}
+}
+
+func assertNames(t *testing.T, label string, objects []BCObject, expected []string) {
+	t.Helper()
+	if len(objects) != len(expected) {
+		t.Fatalf("%s: expected %d objects, got %d (%+v)", label, len(expected), len(objects), objects)
+	}
+	for i, name := range expected {
+		if objects[i].Name != name {
+			t.Errorf("%s: expected object %d to be %q, got %q", label, i, name, objects[i].Name)
+		}
+	}
+}
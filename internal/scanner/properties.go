@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"strings"
+)
+
+// Properties holds the top-level properties of an object, such as
+// PageType = API or Access = Internal, keyed by property name as written in
+// the source. Lookups are case-insensitive like AL itself.
+//
+// Values are the property's source text with string quotes removed, so
+// Caption = 'Customer', Comment = 'x' yields "Customer" and
+// SourceTable = "Sales Header" yields "Sales Header".
+type Properties map[string]string
+
+// Get returns the value of the named property, or "" if it is not set.
+func (p Properties) Get(name string) string {
+	value, _ := p.Lookup(name)
+	return value
+}
+
+// Lookup returns the value of the named property and whether it is set.
+func (p Properties) Lookup(name string) (string, bool) {
+	if value, ok := p[name]; ok {
+		return value, true
+	}
+	for key, value := range p {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Bool reports whether the named property is set to true.
+func (p Properties) Bool(name string) bool {
+	return strings.EqualFold(p.Get(name), "true")
+}
+
+// set records a property, replacing an earlier value for the same name
+// regardless of how it was cased.
+func (p Properties) set(name, value string) {
+	for key := range p {
+		if strings.EqualFold(key, name) {
+			delete(p, key)
+		}
+	}
+	p[name] = value
+}
+
+// isPropertyStart reports whether toks[i] begins a property assignment
+// (Name = value;). Inside an object body properties follow the opening brace
+// or the end of a previous declaration, which rules out comparisons in code
+// such as `if a = b then`.
+func isPropertyStart(toks []Token, i int) bool {
+	if toks[i].Kind != TokenIdent || i+1 >= len(toks) || !toks[i+1].IsPunct("=") || i == 0 {
+		return false
+	}
+	prev := toks[i-1]
+	return prev.IsPunct("{") || prev.IsPunct(";") || prev.IsPunct("}")
+}
+
+// parsePropertyValue reads the value of a property starting at toks[i], the
+// first token after the equals sign, up to the terminating semicolon. It
+// returns the value and the index of the semicolon, or "" and the index of
+// the first token that cannot be part of a value.
+func parsePropertyValue(toks []Token, i int) (string, int) {
+	start := i
+	depth := 0
+	for ; i < len(toks); i++ {
+		tok := toks[i]
+		switch {
+		case tok.IsPunct("(") || tok.IsPunct("["):
+			depth++
+		case tok.IsPunct(")") || tok.IsPunct("]"):
+			depth--
+		case tok.IsPunct("{") || tok.IsPunct("}"):
+			return "", i
+		case tok.IsPunct(";") && depth <= 0:
+			return propertyText(toks[start:i]), i
+		}
+	}
+	return "", i
+}
+
+// propertyText renders the tokens of a property value. A single literal or
+// identifier is returned without quotes; a string followed by further
+// arguments (Caption = 'X', Comment = 'Y') is reduced to the string; other
+// values are rebuilt from the source text with whitespace collapsed.
+func propertyText(toks []Token) string {
+	if len(toks) == 0 {
+		return ""
+	}
+	if len(toks) == 1 || (toks[0].Kind == TokenString && toks[1].IsPunct(",")) {
+		return toks[0].Value
+	}
+
+	var sb strings.Builder
+	for i, tok := range toks {
+		if i > 0 && tok.Offset > toks[i-1].Offset+len(toks[i-1].Text) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(tok.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestScanFileProperties(t *testing.T) {
+	content := `page 50100 "Customer API"
+{
+    PageType = API;
+    Caption = 'Customers', Comment = 'Shown in the API list', Locked = true;
+    APIPublisher = 'contoso';
+    SourceTable = Customer;
+    SourceTableView = sorting("No.") where(Blocked = const(" "));
+    Extensible = false;
+    DelayedInsert = true;
+
+    layout
+    {
+        area(Content)
+        {
+            field(number; Rec."No.")
+            {
+                Caption = 'Number';
+            }
+        }
+    }
+
+    trigger OnOpenPage()
+    var
+        IsHandled: Boolean;
+    begin
+        if IsHandled = true then
+            exit;
+        IsHandled := false;
+    end;
+
+    access = Internal;
+}
+
+codeunit 50101 "Sales Tests"
+{
+    Subtype = Test;
+    TestPermissions = Disabled;
+}
+
+table 50102 "No Properties"
+{
+}
+`
+	result := scanSource([]byte(content), "test.al", nil)
+	if len(result.Objects) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(result.Objects))
+	}
+
+	page := result.Objects[0].Properties
+	expected := map[string]string{
+		"PageType":        "API",
+		"Caption":         "Customers",
+		"APIPublisher":    "contoso",
+		"SourceTable":     "Customer",
+		"SourceTableView": `sorting("No.") where(Blocked = const(" "))`,
+		"Extensible":      "false",
+		"Access":          "Internal",
+	}
+	for name, value := range expected {
+		if got := page.Get(name); got != value {
+			t.Errorf("%s: expected %q, got %q", name, value, got)
+		}
+	}
+	if len(page) != 8 {
+		t.Errorf("expected 8 page properties, got %d: %v", len(page), page)
+	}
+	if !page.Bool("delayedinsert") || page.Bool("Extensible") {
+		t.Errorf("unexpected Bool results for %v", page)
+	}
+
+	codeunit := result.Objects[1].Properties
+	if codeunit.Get("subtype") != "Test" || codeunit.Get("TestPermissions") != "Disabled" {
+		t.Errorf("unexpected codeunit properties %v", codeunit)
+	}
+
+	if props := result.Objects[2].Properties; props != nil {
+		t.Errorf("expected no properties, got %v", props)
+	}
+	if _, ok := result.Objects[2].Properties.Lookup("Caption"); ok {
+		t.Error("lookup on nil properties should report missing")
+	}
+}
@@ -3,61 +3,247 @@
 package scanner
 
 import (
-	"bufio"
+	"context"
 	"os"
 	"path/filepath"
-	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // BCObject represents a Business Central object found in an AL file.
+//
+// Line and Column (1-based) and Offset (0-based bytes) locate the object's
+// declaration keyword. EndLine and EndOffset locate the end of the closing
+// brace of its body, so source[Offset:EndOffset] is the object's full text.
 type BCObject struct {
-	Type     string `json:"type"`
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	FilePath string `json:"filePath"`
+	Type          string   `json:"type"`
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Namespace     string   `json:"namespace,omitempty"`
+	QualifiedName string   `json:"qualifiedName"`
+	Usings        []string `json:"usings,omitempty"`
+	Extends       string   `json:"extends,omitempty"`
+	Implements    []string `json:"implements,omitempty"`
+	FilePath      string   `json:"filePath"`
+	Line          int      `json:"line"`
+	Column        int      `json:"column"`
+	Offset        int      `json:"offset"`
+	EndLine       int      `json:"endLine"`
+	EndOffset     int      `json:"endOffset"`
+	// App is the app the object belongs to: the nearest app.json above its
+	// file, or the package itself for objects read from a .app file.
+	App *AppInfo `json:"app,omitempty"`
+	// Properties are the object-level properties, e.g. PageType or Access.
+	Properties Properties `json:"properties,omitempty"`
+	// Obsolete is set when the object's ObsoleteState is Pending or Removed.
+	Obsolete *Obsolete `json:"obsolete,omitempty"`
+	// Lines counts the code, comment and blank lines from Line to EndLine.
+	Lines LineCounts `json:"lines"`
+	// ObsoleteMembers are the fields, enum values and procedures marked obsolete.
+	ObsoleteMembers []ObsoleteMember `json:"obsoleteMembers,omitempty"`
+	// Members are the fields, keys, procedures, triggers, page controls and
+	// actions, and enum values declared in the object's body.
+	Members []Member `json:"members,omitempty"`
+	// CAL is set for objects read from a legacy C/AL text export.
+	CAL *CALProperties `json:"cal,omitempty"`
 }
 
-// objectPatternWithID matches BC object declarations that REQUIRE an ID.
-// These are: table, tableextension, page, pageextension, report, reportextension,
-// codeunit, xmlport, query, enum, enumextension, permissionset, permissionsetextension
-// Pattern requires: type + ID (digits) + "name"
-var objectPatternWithID = regexp.MustCompile(`(?i)^\s*(tableextension|table|pageextension|page|reportextension|report|codeunit|xmlport|query|enumextension|enum|permissionsetextension|permissionset)\s+(\d+)\s+"([^"]+)"`)
+// FromSource reports whether obj was declared in AL source code rather than
+// read from a compiled .app package or a legacy C/AL export.
+func (obj *BCObject) FromSource() bool {
+	return obj.CAL == nil && !isAppPackage(obj.FilePath)
+}
+
+// objectTypesWithID lists the BC object types whose declarations REQUIRE an ID:
+// type + ID (integer) + "name".
+var objectTypesWithID = map[string]bool{
+	"table":                  true,
+	"tableextension":         true,
+	"page":                   true,
+	"pageextension":          true,
+	"report":                 true,
+	"reportextension":        true,
+	"codeunit":               true,
+	"xmlport":                true,
+	"query":                  true,
+	"enum":                   true,
+	"enumextension":          true,
+	"permissionset":          true,
+	"permissionsetextension": true,
+}
+
+// objectTypesNoID lists the BC object types whose declarations do NOT have an ID:
+// type + "name".
+var objectTypesNoID = map[string]bool{
+	"interface":    true,
+	"profile":      true,
+	"controladdin": true,
+	"entitlement":  true,
+}
+
+// ResultVersion identifies the shape and semantics of scan results. Bump it
+// whenever the scanner produces different output for the same input, so that
+// cached results from older versions are discarded.
+const ResultVersion = 14
+
+// Findings holds the objects and diagnostics found by a scan, of a single
+// file or of a whole directory.
+//
+// InactiveObjects are declared in #if branches that are excluded by the
+// active preprocessor symbols; they are not part of Objects.
+type Findings struct {
+	Objects         []BCObject   `json:"objects"`
+	InactiveObjects []BCObject   `json:"inactiveObjects,omitempty"`
+	Diagnostics     []Diagnostic `json:"diagnostics"`
+}
+
+// Result holds everything found while scanning a directory.
+//
+// Files holds the line counts of every AL source file and C/AL export that
+// was scanned.
+//
+// Apps are the app.json manifests found in the directory or above it, sorted
+// by directory.
+type Result struct {
+	Findings
+	Files []FileMetrics `json:"files,omitempty"`
+	Apps  []AppManifest `json:"apps,omitempty"`
+}
+
+// FileResult holds everything found while scanning a single file.
+//
+// Metrics is nil for files that are not source code, such as .app packages.
+type FileResult struct {
+	Findings
+	Metrics *FileMetrics `json:"metrics,omitempty"`
+}
+
+// Cache stores per-file scan results between runs. Implementations must be
+// safe for concurrent use.
+type Cache interface {
+	// Lookup returns a cached result for key, which identifies the file and
+	// the preprocessor symbols it was scanned with. It is first called with a
+	// nil src and may only compare the file's size and modification time; on a
+	// miss it is called again with the file contents to compare hashes.
+	Lookup(key string, info os.FileInfo, src []byte) (*FileResult, bool)
+	// Store records the result of scanning the file identified by key with contents src.
+	Store(key string, info os.FileInfo, src []byte, result *FileResult)
+}
+
+// Options controls how ScanDirectoryContext walks and scans a directory.
+type Options struct {
+	// Recursive enables scanning of subdirectories.
+	Recursive bool
+	// Jobs is the number of files scanned concurrently; values <= 0 use one worker per CPU.
+	Jobs int
+	// Cache, if set, is consulted before parsing a file and updated afterwards.
+	Cache Cache
+	// IncludeApps also reads compiled .app packages found in the directory.
+	IncludeApps bool
+	// IncludeCAL also reads .txt files found in the directory as C/AL text
+	// exports.
+	IncludeCAL bool
+	// Defines are preprocessor symbols that are active in every file, in
+	// addition to the preprocessorSymbols of the app.json owning the file.
+	Defines []string
+}
 
-// objectPatternNoID matches BC object declarations that do NOT have an ID.
-// These are: interface, profile, controladdin, entitlement
-// Pattern requires: type + "name" (no ID)
-var objectPatternNoID = regexp.MustCompile(`(?i)^\s*(interface|profile|controladdin|entitlement)\s+"([^"]+)"`)
+// fileJob is a file to scan along with the preprocessor symbols active in it.
+// src holds the contents of files that are not read from disk. diagnostics are
+// problems found while walking to the file, such as unreadable directories,
+// which are reported before the file's own.
+type fileJob struct {
+	path        string
+	src         []byte
+	symbols     SymbolSet
+	diagnostics []Diagnostic
+}
 
 // ScanDirectory recursively scans a directory for .al files and extracts BC objects.
-func ScanDirectory(root string, recursive bool) ([]BCObject, error) {
-	var objects []BCObject
+// Problems with individual files or subdirectories are reported as diagnostics
+// and do not stop the scan; only a failure to walk the root itself is returned
+// as an error.
+func ScanDirectory(root string, recursive bool) (*Result, error) {
+	return ScanDirectoryContext(context.Background(), root, Options{Recursive: recursive})
+}
+
+// ScanDirectoryContext is like ScanDirectory but scans files concurrently with
+// a bounded pool of workers and stops early when ctx is cancelled, returning
+// ctx.Err(). Objects and diagnostics are always returned in walk order, no
+// matter in which order the workers finish.
+//
+// root may also be a single .al, .app or .txt file, which is scanned
+// regardless of opts.IncludeApps and opts.IncludeCAL.
+//
+// With opts.IncludeCAL, .txt files are read as well, but only those that turn
+// out to be C/AL text exports yield objects.
+//
+// AL files are preprocessed with the symbols from opts.Defines and from the
+// nearest app.json in the file's directory or above it. Objects are
+// attributed to that app.json's app.
+func ScanDirectoryContext(ctx context.Context, root string, opts Options) (*Result, error) {
+	var jobs []fileJob
+	var pending []Diagnostic
+	manifests := make(map[string]*AppManifest)
 
 	walkFn := func(path string, d os.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
 		if err != nil {
-			return err
+			if path == root {
+				return err
+			}
+			pending = append(pending, Diagnostic{
+				File:     path,
+				Severity: SeverityError,
+				Code:     CodeWalkError,
+				Message:  err.Error(),
+			})
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
 
 		// Skip directories if not recursive (except root)
 		if d.IsDir() {
-			if !recursive && path != root {
+			if !opts.Recursive && path != root {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Only process .al files
-		if !strings.HasSuffix(strings.ToLower(path), ".al") {
+		if isManifest(path) {
+			manifest, err := LoadManifest(path)
+			if err != nil {
+				pending = append(pending, Diagnostic{
+					File:     path,
+					Severity: SeverityWarning,
+					Code:     CodeManifestError,
+					Message:  err.Error(),
+				})
+				return nil
+			}
+			manifests[manifest.Dir] = manifest
 			return nil
 		}
 
-		fileObjects, err := ScanFile(path)
-		if err != nil {
-			// Log error but continue scanning other files
+		// Only process .al files, plus .app packages and C/AL exports when requested
+		switch {
+		case isALFile(path):
+		case isTextFile(path) && (opts.IncludeCAL || path == root):
+		case isAppPackage(path) && (opts.IncludeApps || path == root):
+		default:
 			return nil
 		}
 
-		objects = append(objects, fileObjects...)
+		jobs = append(jobs, fileJob{path: path, diagnostics: pending})
+		pending = nil
 		return nil
 	}
 
@@ -66,78 +252,342 @@ func ScanDirectory(root string, recursive bool) ([]BCObject, error) {
 		return nil, err
 	}
 
-	return objects, nil
+	// The app.json owning root may live above it, e.g. when scanning src/
+	if parent := findManifest(root); parent != nil {
+		if _, ok := manifests[parent.Dir]; !ok {
+			manifests[parent.Dir] = parent
+		}
+	}
+
+	return scanJobs(ctx, jobs, pending, manifests, opts)
 }
 
-// ScanFile scans a single AL file and extracts BC objects.
-func ScanFile(filePath string) ([]BCObject, error) {
-	file, err := os.Open(filePath)
+// Source is a file to scan from memory rather than from disk, such as a blob
+// read from a git tree.
+type Source struct {
+	Path string
+	Data []byte
+}
+
+// ScanSources scans in-memory files the way ScanDirectoryContext scans a
+// directory: app.json sources provide the preprocessor symbols and the app of
+// the sources in their directory and below. Paths are reported as given.
+// opts.Recursive and opts.Cache are ignored.
+func ScanSources(ctx context.Context, sources []Source, opts Options) (*Result, error) {
+	manifests := make(map[string]*AppManifest)
+
+	var jobs []fileJob
+	var pending []Diagnostic
+	for _, src := range sources {
+		if isManifest(src.Path) {
+			manifest, err := parseManifest(src.Data, src.Path)
+			if err != nil {
+				pending = append(pending, Diagnostic{
+					File:     src.Path,
+					Severity: SeverityWarning,
+					Code:     CodeManifestError,
+					Message:  err.Error(),
+				})
+				continue
+			}
+			manifests[manifest.Dir] = manifest
+			continue
+		}
+
+		switch {
+		case isALFile(src.Path):
+		case isTextFile(src.Path) && opts.IncludeCAL:
+		case isAppPackage(src.Path) && opts.IncludeApps:
+		default:
+			continue
+		}
+		jobs = append(jobs, fileJob{path: src.Path, src: src.Data, diagnostics: pending})
+		pending = nil
+	}
+
+	opts.Cache = nil
+	return scanJobs(ctx, jobs, pending, manifests, opts)
+}
+
+// scanJobs scans files with the preprocessor symbols of their owning
+// manifests and attributes their objects to those apps. Diagnostics are
+// returned in job order, each job's walk diagnostics before its own;
+// trailing holds those found after the last job.
+func scanJobs(ctx context.Context, jobs []fileJob, trailing []Diagnostic, manifests map[string]*AppManifest, opts Options) (*Result, error) {
+	result := &Result{}
+	for _, manifest := range manifests {
+		result.Apps = append(result.Apps, *manifest)
+	}
+	sort.Slice(result.Apps, func(i, j int) bool {
+		return result.Apps[i].Dir < result.Apps[j].Dir
+	})
+
+	for i := range jobs {
+		var symbols []string
+		if manifest := owningManifest(jobs[i].path, manifests); manifest != nil {
+			symbols = manifest.PreprocessorSymbols
+		}
+		jobs[i].symbols = NewSymbolSet(append(append([]string(nil), symbols...), opts.Defines...)...)
+	}
+
+	fileResults, err := scanFiles(ctx, jobs, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	var objects []BCObject
-	scanner := bufio.NewScanner(file)
-	inBlockComment := false
+	for i, fileResult := range fileResults {
+		// Packages identify their own app; sources belong to the owning app.json
+		if !isAppPackage(jobs[i].path) {
+			var app *AppInfo
+			if manifest := owningManifest(jobs[i].path, manifests); manifest != nil {
+				app = manifest.Info()
+			}
+			setApp(fileResult, app)
+		}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+		result.Objects = append(result.Objects, fileResult.Objects...)
+		result.InactiveObjects = append(result.InactiveObjects, fileResult.InactiveObjects...)
+		if fileResult.Metrics != nil {
+			result.Files = append(result.Files, *fileResult.Metrics)
+		}
+		result.Diagnostics = append(result.Diagnostics, jobs[i].diagnostics...)
+		result.Diagnostics = append(result.Diagnostics, fileResult.Diagnostics...)
+	}
+	result.Diagnostics = append(result.Diagnostics, trailing...)
 
-		// Handle block comments
-		if strings.Contains(line, "/*") {
-			inBlockComment = true
+	return result, nil
+}
+
+// scanFiles scans files using at most opts.Jobs concurrent workers. The
+// results slice is indexed like files, which keeps the output deterministic.
+func scanFiles(ctx context.Context, files []fileJob, opts Options) ([]*FileResult, error) {
+	workers := opts.Jobs
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	results := make([]*FileResult, len(files))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = scanPath(files[i], opts.Cache)
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
 		}
-		if strings.Contains(line, "*/") {
-			inBlockComment = false
-			continue
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// scanPath scans a single file, reporting a read failure as a diagnostic.
+// Results are taken from and saved to cache when one is given.
+func scanPath(job fileJob, cache Cache) *FileResult {
+	path := job.path
+	if job.src != nil {
+		return scanContent(job.src, path, job.symbols)
+	}
+	key := cacheKey(path, job.symbols)
+
+	readError := func(err error) *FileResult {
+		return &FileResult{Findings: Findings{
+			Diagnostics: []Diagnostic{{
+				File:     path,
+				Severity: SeverityError,
+				Code:     CodeReadError,
+				Message:  err.Error(),
+			}},
+		}}
+	}
+
+	var info os.FileInfo
+	if cache != nil {
+		var err error
+		info, err = os.Stat(path)
+		if err != nil {
+			return readError(err)
 		}
-		if inBlockComment {
-			continue
+		if cached, ok := cache.Lookup(key, info, nil); ok {
+			return cached
 		}
+	}
 
-		// Skip single-line comments
-		trimmedLine := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmedLine, "//") {
-			continue
-		}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return readError(err)
+	}
 
-		// Try to match object declaration
-		if obj := ParseObjectLine(line, filePath); obj != nil {
-			objects = append(objects, *obj)
+	if cache == nil {
+		return scanContent(src, path, job.symbols)
+	}
+	if cached, ok := cache.Lookup(key, info, src); ok {
+		return cached
+	}
+
+	fileResult := scanContent(src, path, job.symbols)
+	cache.Store(key, info, src, fileResult)
+	return fileResult
+}
+
+// cacheKey identifies a file scanned with a set of preprocessor symbols, so
+// that changing the symbols does not reuse results computed without them.
+func cacheKey(path string, symbols SymbolSet) string {
+	if len(symbols) == 0 {
+		return path
+	}
+	return path + "#" + strings.Join(symbols.Sorted(), ";")
+}
+
+// scanContent extracts BC objects from the contents of a file, choosing the
+// parser by file extension. symbols only affect AL source files.
+func scanContent(src []byte, path string, symbols SymbolSet) *FileResult {
+	if isTextFile(path) {
+		if !isCALExport(src) {
+			return &FileResult{}
 		}
+		return scanCALSource(src, path)
+	}
+	if !isAppPackage(path) {
+		return scanSource(src, path, symbols)
 	}
 
-	if err := scanner.Err(); err != nil {
+	fileResult, err := scanAppPackage(src, path)
+	if err != nil {
+		return &FileResult{Findings: Findings{
+			Diagnostics: []Diagnostic{{
+				File:     path,
+				Severity: SeverityError,
+				Code:     CodePackageError,
+				Message:  err.Error(),
+			}},
+		}}
+	}
+	return fileResult
+}
+
+// isALFile reports whether path is an AL source file.
+func isALFile(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".al")
+}
+
+// isTextFile reports whether path is a .txt file, possibly a C/AL export.
+func isTextFile(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".txt")
+}
+
+// isAppPackage reports whether path is a compiled .app package.
+func isAppPackage(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".app")
+}
+
+// ScanFile scans a single file (AL source, .app package or C/AL text export)
+// and extracts BC objects, using the preprocessor symbols of the nearest
+// app.json above the file and attributing the objects to its app.
+// The returned error is only set if the file could not be read; problems
+// inside the file are reported as diagnostics.
+func ScanFile(filePath string) (*FileResult, error) {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
 		return nil, err
 	}
 
-	return objects, nil
+	if isAppPackage(filePath) {
+		return scanContent(src, filePath, nil), nil
+	}
+
+	manifest := findManifest(filePath)
+	if manifest == nil {
+		return scanContent(src, filePath, nil), nil
+	}
+	result := scanContent(src, filePath, NewSymbolSet(manifest.PreprocessorSymbols...))
+	setApp(result, manifest.Info())
+	return result, nil
+}
+
+// scanSource extracts BC objects and diagnostics from AL source code.
+// Objects in conditional branches that are inactive under symbols are
+// returned as InactiveObjects.
+func scanSource(src []byte, filePath string, symbols SymbolSet) *FileResult {
+	lx := NewLexer(src)
+	var tokens []Token
+	for {
+		tok := lx.Next()
+		tokens = append(tokens, tok)
+		if tok.Kind == TokenEOF {
+			break
+		}
+	}
+
+	result := &FileResult{}
+	for _, e := range lx.Errors() {
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			File:     filePath,
+			Line:     e.Line,
+			Column:   e.Column,
+			Severity: SeverityWarning,
+			Code:     CodeLexError,
+			Message:  e.Message,
+		})
+	}
+
+	active, inactive, diagnostics := applyPreprocessor(tokens, symbols, filePath)
+	result.Diagnostics = append(result.Diagnostics, diagnostics...)
+
+	objects, diagnostics := parseObjects(active, filePath)
+	result.Objects = objects
+	result.Diagnostics = append(result.Diagnostics, diagnostics...)
+
+	// Inactive branches are often fragments of a larger construct, so
+	// problems found in them are not worth reporting
+	if topLevel := topLevelInactive(active, inactive); len(topLevel) > 0 {
+		result.InactiveObjects, _ = parseObjects(topLevel, filePath)
+	}
+
+	applyLineMetrics(result, filePath, classifyLines(src, tokens, inactive))
+	return result
 }
 
 // ParseObjectLine attempts to parse a BC object declaration from a single line.
 func ParseObjectLine(line, filePath string) *BCObject {
-	// First try to match objects that require an ID (table, page, codeunit, etc.)
-	if matches := objectPatternWithID.FindStringSubmatch(line); matches != nil {
-		return &BCObject{
-			Type:     strings.ToLower(matches[1]),
-			ID:       matches[2],
-			Name:     matches[3],
-			FilePath: filePath,
-		}
+	objects, _ := parseObjects(Tokenize([]byte(line)), filePath)
+	if len(objects) == 0 {
+		return nil
 	}
+	return &objects[0]
+}
 
-	// Then try to match objects without an ID (interface, profile, controladdin, entitlement)
-	if matches := objectPatternNoID.FindStringSubmatch(line); matches != nil {
-		return &BCObject{
-			Type:     strings.ToLower(matches[1]),
-			ID:       "",
-			Name:     matches[2],
-			FilePath: filePath,
-		}
+// NumericID converts an object ID for sorting; non-numeric IDs sort first.
+func NumericID(id string) int {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return -1
 	}
+	return n
+}
 
-	return nil
+// TypeHasID reports whether declarations of objType carry an object ID.
+func TypeHasID(objType string) bool {
+	return objectTypesWithID[objType]
 }
 
 // GetSupportedObjectTypes returns a list of all supported BC object types.
@@ -160,5 +610,6 @@ func GetSupportedObjectTypes() []string {
 		"profile",
 		"controladdin",
 		"entitlement",
+		"menusuite", // legacy C/AL only
 	}
 }
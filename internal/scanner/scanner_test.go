@@ -1,8 +1,12 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -180,6 +184,33 @@ func TestParseObjectLine(t *testing.T) {
 				Name: "My Control Addin",
 			},
 		},
+		{
+			name: "unquoted table name",
+			line: `table 50100 MyTable`,
+			expected: &BCObject{
+				Type: "table",
+				ID:   "50100",
+				Name: "MyTable",
+			},
+		},
+		{
+			name: "unquoted tableextension name and target",
+			line: `tableextension 50100 MyTableExt extends Customer`,
+			expected: &BCObject{
+				Type: "tableextension",
+				ID:   "50100",
+				Name: "MyTableExt",
+			},
+		},
+		{
+			name: "unquoted interface name",
+			line: `interface IMyInterface`,
+			expected: &BCObject{
+				Type: "interface",
+				ID:   "",
+				Name: "IMyInterface",
+			},
+		},
 		// Permission set reference tests - these should NOT match
 		{
 			name:     "permission reference - table without ID",
@@ -316,10 +347,11 @@ page 50100 "Customer Card Ext"
 		t.Fatal(err)
 	}
 
-	objects, err := ScanFile(tmpFile)
+	result, err := ScanFile(tmpFile)
 	if err != nil {
 		t.Fatal(err)
 	}
+	objects := result.Objects
 
 	if len(objects) != 3 {
 		t.Errorf("expected 3 objects, got %d", len(objects))
@@ -343,6 +375,165 @@ page 50100 "Customer Card Ext"
 	}
 }
 
+func TestScanFileCommentsAndStrings(t *testing.T) {
+	// A "/*" inside a string literal must not open a comment, and a block
+	// comment that opens and closes on one line must not hide what follows.
+	content := `codeunit 50100 "Path Helper"
+{
+    var
+        Pattern: Label '/* not a comment';
+}
+
+/* inline */ table 50101 "After Inline Comment"
+{
+}
+
+/*
+page 50102 "Inside Block Comment"
+{
+}
+*/ page 50103 "After Block Comment"
+{
+}
+`
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "comments.al")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := result.Objects
+
+	expectedNames := []string{"Path Helper", "After Inline Comment", "After Block Comment"}
+	if len(objects) != len(expectedNames) {
+		for _, obj := range objects {
+			t.Logf("  found: %s %s %q", obj.Type, obj.ID, obj.Name)
+		}
+		t.Fatalf("expected %d objects, got %d", len(expectedNames), len(objects))
+	}
+
+	for i, name := range expectedNames {
+		if objects[i].Name != name {
+			t.Errorf("object %d: expected name %q, got %q", i, name, objects[i].Name)
+		}
+	}
+}
+
+func TestScanFileHeaderForms(t *testing.T) {
+	// Fixtures cover unquoted names, headers split across lines and
+	// extends/implements clauses.
+	tests := []struct {
+		file     string
+		expected []BCObject
+	}{
+		{
+			file: "unquoted.al",
+			expected: []BCObject{
+				{Type: "table", ID: "50200", Name: "UnquotedTable"},
+				{Type: "interface", ID: "", Name: "IUnquotedInterface"},
+				{Type: "codeunit", ID: "50200", Name: "UnquotedCodeunit", Implements: []string{"IUnquotedInterface"}},
+			},
+		},
+		{
+			file: "multiline.al",
+			expected: []BCObject{
+				{Type: "codeunit", ID: "50201", Name: "Split Codeunit"},
+				{Type: "page", ID: "50201", Name: "SplitPage"},
+				{Type: "enum", ID: "50201", Name: "Split Enum", Implements: []string{"IUnquotedInterface", "ISecondInterface"}},
+				{Type: "profile", ID: "", Name: "Split Profile"},
+			},
+		},
+		{
+			file: "extends.al",
+			expected: []BCObject{
+				{Type: "tableextension", ID: "50202", Name: "Quoted Ext", Extends: "Sales Header"},
+				{Type: "tableextension", ID: "50203", Name: "UnquotedExt", Extends: "Customer"},
+				{Type: "pageextension", ID: "50202", Name: "Split Page Ext", Extends: "Customer Card"},
+				{Type: "enumextension", ID: "50202", Name: "Enum Ext", Extends: "Sales Document Type"},
+				{Type: "permissionsetextension", ID: "50202", Name: "PermExt", Extends: "D365 BASIC"},
+				{Type: "tableextension", ID: "50204", Name: "Qualified Ext", Extends: "Microsoft.Sales.Customer.Customer"},
+				{Type: "pageextension", ID: "50203", Name: "QualifiedPageExt", Extends: `Microsoft.Sales."Sales Order"`},
+				{Type: "codeunit", ID: "50202", Name: "Qualified Shipper", Implements: []string{"Contoso.Api.IShipping", "IPricing"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			result, err := ScanFile(filepath.Join("..", "..", "testdata", "headers", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			objects := result.Objects
+
+			if len(objects) != len(tt.expected) {
+				for _, obj := range objects {
+					t.Logf("  found: %s %s %q", obj.Type, obj.ID, obj.Name)
+				}
+				t.Fatalf("expected %d objects, got %d", len(tt.expected), len(objects))
+			}
+
+			for i, exp := range tt.expected {
+				if objects[i].Type != exp.Type || objects[i].ID != exp.ID || objects[i].Name != exp.Name {
+					t.Errorf("object %d: expected %s %s %q, got %s %s %q",
+						i, exp.Type, exp.ID, exp.Name, objects[i].Type, objects[i].ID, objects[i].Name)
+				}
+				if objects[i].Extends != exp.Extends {
+					t.Errorf("object %d: expected extends %q, got %q", i, exp.Extends, objects[i].Extends)
+				}
+				if strings.Join(objects[i].Implements, ",") != strings.Join(exp.Implements, ",") {
+					t.Errorf("object %d: expected implements %v, got %v", i, exp.Implements, objects[i].Implements)
+				}
+			}
+		})
+	}
+}
+
+func TestUnqualifiedName(t *testing.T) {
+	tests := map[string]string{
+		"Customer":                          "Customer",
+		"Microsoft.Sales.Customer.Customer": "Customer",
+		`Microsoft.Sales."Sales Header"`:    "Sales Header",
+		`"Sales.Header"`:                    "Sales.Header",
+		`Contoso."Sales.Header"`:            "Sales.Header",
+	}
+	for name, expected := range tests {
+		if got := UnqualifiedName(name); got != expected {
+			t.Errorf("UnqualifiedName(%q): expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestFromSource(t *testing.T) {
+	tests := []struct {
+		obj      BCObject
+		expected bool
+	}{
+		{BCObject{FilePath: "src/Customer.Table.al"}, true},
+		{BCObject{FilePath: "Contoso_Sales_1.0.0.0.APP"}, false},
+		{BCObject{FilePath: "nav.txt", CAL: &CALProperties{}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.obj.FromSource(); got != tt.expected {
+			t.Errorf("FromSource(%s): expected %v, got %v", tt.obj.FilePath, tt.expected, got)
+		}
+	}
+}
+
+func TestNumericID(t *testing.T) {
+	tests := map[string]int{"50100": 50100, "0": 0, "": -1, "ABC": -1}
+	for id, expected := range tests {
+		if got := NumericID(id); got != expected {
+			t.Errorf("NumericID(%q): expected %d, got %d", id, expected, got)
+		}
+	}
+}
+
 func TestScanFilePermissionSet(t *testing.T) {
 	// Test that permission set files only count the permissionset itself,
 	// not the table/codeunit/page references inside
@@ -371,10 +562,11 @@ func TestScanFilePermissionSet(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	objects, err := ScanFile(tmpFile)
+	result, err := ScanFile(tmpFile)
 	if err != nil {
 		t.Fatal(err)
 	}
+	objects := result.Objects
 
 	// Should only find 1 object: the permissionset declaration
 	if len(objects) != 1 {
@@ -435,10 +627,11 @@ entitlement "MyEntitlement"
 		t.Fatal(err)
 	}
 
-	objects, err := ScanFile(tmpFile)
+	result, err := ScanFile(tmpFile)
 	if err != nil {
 		t.Fatal(err)
 	}
+	objects := result.Objects
 
 	// Should find 5 objects
 	if len(objects) != 5 {
@@ -511,23 +704,23 @@ page 50100 "Sub Page"
 	}
 
 	// Test recursive scan
-	objects, err := ScanDirectory(tmpDir, true)
+	result, err := ScanDirectory(tmpDir, true)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if len(objects) != 3 {
-		t.Errorf("recursive: expected 3 objects, got %d", len(objects))
+	if len(result.Objects) != 3 {
+		t.Errorf("recursive: expected 3 objects, got %d", len(result.Objects))
 	}
 
 	// Test non-recursive scan
-	objects, err = ScanDirectory(tmpDir, false)
+	result, err = ScanDirectory(tmpDir, false)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if len(objects) != 1 {
-		t.Errorf("non-recursive: expected 1 object, got %d", len(objects))
+	if len(result.Objects) != 1 {
+		t.Errorf("non-recursive: expected 1 object, got %d", len(result.Objects))
 	}
 }
 
@@ -553,3 +746,220 @@ func TestGetSupportedObjectTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestScanFileNamespaces(t *testing.T) {
+	content := `namespace Contoso.Sales;
+
+using Microsoft.Sales.Customer;
+using Microsoft.Foundation.NoSeries;
+
+table 50100 "Sales Budget"
+{
+}
+
+codeunit 50100 BudgetMgt
+{
+}
+`
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "namespace.al")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := result.Objects
+
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objects))
+	}
+
+	expectedQualified := []string{`Contoso.Sales."Sales Budget"`, "Contoso.Sales.BudgetMgt"}
+	for i, obj := range objects {
+		if obj.Namespace != "Contoso.Sales" {
+			t.Errorf("object %d: expected namespace Contoso.Sales, got %q", i, obj.Namespace)
+		}
+		if obj.QualifiedName != expectedQualified[i] {
+			t.Errorf("object %d: expected qualified name %s, got %s", i, expectedQualified[i], obj.QualifiedName)
+		}
+		if len(obj.Usings) != 2 || obj.Usings[0] != "Microsoft.Sales.Customer" {
+			t.Errorf("object %d: unexpected usings %v", i, obj.Usings)
+		}
+	}
+}
+
+func TestParseObjectLineQualifiedNameWithoutNamespace(t *testing.T) {
+	obj := ParseObjectLine(`table 50100 "My Table"`, "test.al")
+	if obj == nil {
+		t.Fatal("expected object, got nil")
+	}
+	if obj.Namespace != "" || obj.QualifiedName != "My Table" {
+		t.Errorf("expected no namespace and qualified name %q, got %q / %q", "My Table", obj.Namespace, obj.QualifiedName)
+	}
+}
+
+func TestScanFilePositions(t *testing.T) {
+	path := filepath.Join("..", "..", "testdata", "more", "misc.al")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := result.Objects
+
+	expected := []struct {
+		name            string
+		line, column    int
+		endLine         int
+		firstLine, last string
+	}{
+		{"Sample Status", 1, 1, 7, `enum 50100 "Sample Status"`, "}"},
+		{"Sample Report", 9, 1, 19, `report 50100 "Sample Report"`, "}"},
+		{"Customer Ext", 21, 1, 30, `tableextension 50100 "Customer Ext" extends Customer`, "}"},
+	}
+
+	if len(objects) != len(expected) {
+		t.Fatalf("expected %d objects, got %d", len(expected), len(objects))
+	}
+
+	for i, exp := range expected {
+		obj := objects[i]
+		if obj.Name != exp.name {
+			t.Errorf("object %d: expected name %q, got %q", i, exp.name, obj.Name)
+		}
+		if obj.Line != exp.line || obj.Column != exp.column || obj.EndLine != exp.endLine {
+			t.Errorf("object %d: expected %d:%d-%d, got %d:%d-%d",
+				i, exp.line, exp.column, exp.endLine, obj.Line, obj.Column, obj.EndLine)
+		}
+
+		text := string(src[obj.Offset:obj.EndOffset])
+		if !strings.HasPrefix(text, exp.firstLine) || !strings.HasSuffix(text, exp.last) {
+			t.Errorf("object %d: unexpected source slice %q", i, text)
+		}
+	}
+}
+
+func TestScanFileDiagnostics(t *testing.T) {
+	content := `codeunit 50100 "Broken"
+{
+    procedure Run()
+    begin
+        Message('unterminated);
+    end;
+`
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "broken.al")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Objects) != 1 {
+		t.Errorf("expected the object to still be found, got %d objects", len(result.Objects))
+	}
+
+	codes := make(map[string]Diagnostic)
+	for _, d := range result.Diagnostics {
+		codes[d.Code] = d
+	}
+	if d, ok := codes[CodeLexError]; !ok || d.Line != 5 || d.Severity != SeverityWarning {
+		t.Errorf("expected lex-error warning on line 5, got %+v", result.Diagnostics)
+	}
+	if d, ok := codes[CodeUnclosedBlock]; !ok || d.Line != 1 {
+		t.Errorf("expected unclosed-block warning on line 1, got %+v", result.Diagnostics)
+	}
+	if CountDiagnostics(result.Diagnostics, SeverityError) > 0 {
+		t.Error("expected only warnings")
+	}
+}
+
+func TestScanDirectoryUnreadableFile(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("file permissions are not enforced for root")
+	}
+
+	tmpDir := t.TempDir()
+	good := filepath.Join(tmpDir, "good.al")
+	if err := os.WriteFile(good, []byte(`table 50100 "Good" { }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bad := filepath.Join(tmpDir, "bad.al")
+	if err := os.WriteFile(bad, []byte(`table 50101 "Bad" { }`), 0000); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ScanDirectory(tmpDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Objects) != 1 {
+		t.Errorf("expected 1 object, got %d", len(result.Objects))
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Code != CodeReadError || result.Diagnostics[0].File != bad {
+		t.Errorf("expected one read-error diagnostic for %s, got %+v", bad, result.Diagnostics)
+	}
+	if CountDiagnostics(result.Diagnostics, SeverityError) == 0 {
+		t.Error("expected an error diagnostic")
+	}
+}
+
+func TestScanDirectoryContextDeterministicOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 50; i++ {
+		dir := filepath.Join(tmpDir, fmt.Sprintf("dir%02d", i%5))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := fmt.Sprintf("codeunit %d \"Codeunit %d\"\n{\n}\n", 50000+i, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("cu%02d.al", i)), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sequential, err := ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, Jobs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := ScanDirectoryContext(context.Background(), tmpDir, Options{Recursive: true, Jobs: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sequential.Objects) != 50 || len(parallel.Objects) != 50 {
+		t.Fatalf("expected 50 objects, got %d sequential and %d parallel", len(sequential.Objects), len(parallel.Objects))
+	}
+	for i := range sequential.Objects {
+		if sequential.Objects[i].FilePath != parallel.Objects[i].FilePath || sequential.Objects[i].ID != parallel.Objects[i].ID {
+			t.Fatalf("object %d differs: %+v vs %+v", i, sequential.Objects[i], parallel.Objects[i])
+		}
+	}
+}
+
+func TestScanDirectoryContextCancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.al"), []byte(`table 50100 "A" { }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ScanDirectoryContext(ctx, tmpDir, Options{Recursive: true, Jobs: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+tableextension 50202 "Quoted Ext" extends "Sales Header"
+{
+}
+
+tableextension 50203 UnquotedExt extends Customer
+{
+}
+
+pageextension 50202 "Split Page Ext"
+    extends
+    "Customer Card"
+{
+}
+
+enumextension 50202 "Enum Ext" extends "Sales Document Type"
+{
+}
+
+permissionsetextension 50202 PermExt
+    extends "D365 BASIC"
+{
+}
+
+tableextension 50204 "Qualified Ext" extends Microsoft.Sales.Customer.Customer
+{
+}
+
+pageextension 50203 QualifiedPageExt extends Microsoft.Sales."Sales Order"
+{
+}
+
+codeunit 50202 "Qualified Shipper" implements Contoso.Api."IShipping", IPricing
+{
+}
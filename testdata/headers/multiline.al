@@ -0,0 +1,24 @@
+codeunit 50201
+    "Split Codeunit"
+{
+}
+
+page
+    50201
+    SplitPage
+{
+    PageType = Card;
+}
+
+enum 50201 "Split Enum"
+    implements
+        "IUnquotedInterface",
+        ISecondInterface
+{
+    value(0; None) { }
+}
+
+profile
+    "Split Profile"
+{
+}
@@ -0,0 +1,19 @@
+table 50200 UnquotedTable
+{
+    fields
+    {
+        field(1; Code; Code[20]) { }
+    }
+}
+
+interface IUnquotedInterface
+{
+    procedure Run();
+}
+
+codeunit 50200 UnquotedCodeunit implements IUnquotedInterface
+{
+    procedure Run()
+    begin
+    end;
+}